@@ -24,14 +24,14 @@ func (db DB) GetWithContext(entity Record, ctx map[string]interface{}, ids ...go
 
 	ok, err := db.get(txn, entity, ctx, ids...)
 
-	if db.Options.DebugMode {
-		var n int
-		if ok {
-			n = 1
-		}
-		debugLogGet(entity, t, n, err, ids...)
+	var n int
+	if ok {
+		n = 1
 	}
 
+	db.observe(OpGet, KeyRoot(entity), t, n, err)
+	db.debugLogGet(entity, t, n, err, ids...)
+
 	return ok, err
 }
 
@@ -47,9 +47,8 @@ func (db DB) GetIDsWithContext(target interface{}, ctx map[string]interface{}, i
 
 	n, err := db.getIDsWithContext(txn, target, ctx, ids...)
 
-	if db.Options.DebugMode {
-		debugLogGet(target, t, n, err, ids...)
-	}
+	db.observe(OpGet, KeyRoot(target), t, n, err)
+	db.debugLogGet(target, t, n, err, ids...)
 
 	return n, err
 }
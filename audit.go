@@ -0,0 +1,210 @@
+package tormenta
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Audit trail
+// With Options.AuditTrail and/or Options.Auditor set, every save and delete produces an AuditEntry
+// recording who made the change (the actor, taken from the context passed to SaveWithContext
+// or DeleteWithContext under AuditActorKey) and which fields changed, with their old and new values.
+// The diff is between the record as it is in the DB, which Save already loads for deindexing,
+// and the record being saved, and covers the saved fields, apart from those of the model.
+// With Options.AuditTrail, entries are saved as AuditEntry records in the same transaction as the change,
+// so the change and its audit entry are written together or not at all.
+
+// Context key for the actor making a change
+const AuditActorKey = "actor"
+
+// AuditEntry records a change to a record
+type AuditEntry struct {
+	Model
+
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   gouuidv6.UUID
+	Changes    []FieldChange `tormenta:"noindex"`
+}
+
+// FieldChange is the old and new value of a changed field.
+// Old is nil for created records, and New is nil for deleted records.
+// Values read back from the audit trail are as unserialised into an interface{},
+// e.g. numbers are float64 with the default JSON serialiser.
+type FieldChange struct {
+	Field    string
+	Old, New interface{}
+}
+
+// Auditor is a hook for exporting audit entries, e.g. to an external log.
+// Audit is called synchronously after the change has been committed.
+type Auditor interface {
+	Audit(AuditEntry)
+}
+
+func (db DB) auditing() bool {
+	return db.Options.AuditTrail || db.Options.Auditor != nil
+}
+
+// auditEntry builds the audit entry for a change to a record from its old and new fields,
+// returning nil if there is nothing to audit
+func (db DB) auditEntry(ctx map[string]interface{}, action string, root []byte, id gouuidv6.UUID, oldFields, newFields map[string]interface{}) (*AuditEntry, error) {
+	if !db.auditing() {
+		return nil, nil
+	}
+
+	changes, err := db.fieldChanges(oldFields, newFields)
+	if err != nil {
+		return nil, err
+	}
+
+	// Saving a record without changing it is not worth an entry
+	if action == EventUpdated && len(changes) == 0 {
+		return nil, nil
+	}
+
+	entry := &AuditEntry{
+		Action:     action,
+		EntityType: string(root),
+		EntityID:   id,
+		Changes:    changes,
+	}
+
+	if actor, ok := ctx[AuditActorKey]; ok && actor != nil {
+		entry.Actor = fmt.Sprint(actor)
+	}
+
+	return entry, nil
+}
+
+// fieldChanges compares the serialised values of each field,
+// so that e.g. times are equal regardless of their monotonic clock reading
+func (db DB) fieldChanges(oldFields, newFields map[string]interface{}) ([]FieldChange, error) {
+	fieldNames := map[string]bool{}
+	for fieldName := range oldFields {
+		fieldNames[fieldName] = true
+	}
+
+	for fieldName := range newFields {
+		fieldNames[fieldName] = true
+	}
+
+	var changes []FieldChange
+	for fieldName := range fieldNames {
+		if isModelField(fieldName) {
+			continue
+		}
+
+		oldValue, inOld := oldFields[fieldName]
+		newValue, inNew := newFields[fieldName]
+
+		if inOld && inNew {
+			oldData, err := db.serialise(oldValue)
+			if err != nil {
+				return nil, err
+			}
+
+			newData, err := db.serialise(newValue)
+			if err != nil {
+				return nil, err
+			}
+
+			if bytes.Equal(oldData, newData) {
+				continue
+			}
+		}
+
+		changes = append(changes, FieldChange{
+			Field: fieldName,
+			Old:   oldValue,
+			New:   newValue,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes, nil
+}
+
+func isModelField(fieldName string) bool {
+	_, ok := reflect.TypeOf(Model{}).FieldByName(fieldName)
+	return ok
+}
+
+// saveAuditEntry writes an audit entry to the DB in the transaction of the change it records
+func (db DB) saveAuditEntry(txn *badger.Txn, entry *AuditEntry) error {
+	if !db.Options.AuditTrail || entry == nil {
+		return nil
+	}
+
+	entry.ID = newID()
+	entry.LastUpdated = time.Now().UTC()
+
+	data, err := db.serialise(removeSkippedFields(recordValue(entry)))
+	if err != nil {
+		return err
+	}
+
+	key := newContentKey(KeyRoot(entry), entry.ID).bytes()
+	if err := txn.SetEntry(badger.NewEntry(key, data).WithMeta(userMetaCreated)); err != nil {
+		return err
+	}
+
+	return db.index(txn, entry)
+}
+
+// reportAudit passes the audit entries of committed changes to the auditor
+func (db DB) reportAudit(entries []*AuditEntry) {
+	if db.Options.Auditor == nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.ID.IsNil() {
+			entry.ID = newID()
+			entry.LastUpdated = time.Now().UTC()
+		}
+
+		entry.GetCreated()
+		db.Options.Auditor.Audit(*entry)
+	}
+}
+
+// AuditTrail retrieves the audit entries for the record with the given ID,
+// oldest first, optionally limited to those between from and to (either can be zero)
+func (db DB) AuditTrail(id gouuidv6.UUID, from, to time.Time) ([]AuditEntry, error) {
+	t := time.Now()
+
+	var entries []AuditEntry
+	q := db.Find(&entries).Match("EntityID", id)
+
+	if !from.IsZero() {
+		q.From(from)
+	}
+
+	if !to.IsZero() {
+		q.To(to)
+	}
+
+	txn := db.KV.NewTransaction(false)
+	defer txn.Discard()
+
+	var n int
+	ids, err := q.resultIDs(txn)
+	if err == nil {
+		n, err = db.getIDsWithContext(txn, &entries, noCTX, ids...)
+	}
+
+	db.observe(OpGet, KeyRoot(&AuditEntry{}), t, n, err)
+	db.debugLogGet(&entries, t, n, err, id)
+	return entries, err
+}
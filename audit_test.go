@@ -0,0 +1,130 @@
+package tormenta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+type testAuditor struct {
+	entries []tormenta.AuditEntry
+}
+
+func (a *testAuditor) Audit(entry tormenta.AuditEntry) {
+	a.entries = append(a.entries, entry)
+}
+
+func Test_AuditTrail(t *testing.T) {
+	auditor := &testAuditor{}
+	options := testDBOptions
+	options.AuditTrail = true
+	options.Auditor = auditor
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", options)
+	defer db.Close()
+
+	invoice := testtypes.Invoice{Number: 1, Amount: 100}
+	db.SaveWithContext(map[string]interface{}{tormenta.AuditActorKey: "alice"}, &invoice)
+	time.Sleep(10 * time.Millisecond)
+	afterCreation := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	invoice.Amount = 200
+	db.SaveWithContext(map[string]interface{}{tormenta.AuditActorKey: "bob"}, &invoice)
+
+	// Saving without changes, and saving other records, doesn't add entries
+	db.Save(&invoice)
+	db.Save(&testtypes.Invoice{Number: 2})
+
+	db.DeleteWithContext(&testtypes.Invoice{}, map[string]interface{}{tormenta.AuditActorKey: "carol"}, invoice.ID)
+
+	expected := []struct {
+		actor   string
+		action  string
+		changes []tormenta.FieldChange
+	}{
+		{"alice", tormenta.EventCreated, []tormenta.FieldChange{
+			{Field: "Amount", Old: nil, New: float64(100)},
+			{Field: "Number", Old: nil, New: float64(1)},
+		}},
+		{"bob", tormenta.EventUpdated, []tormenta.FieldChange{
+			{Field: "Amount", Old: float64(100), New: float64(200)},
+		}},
+		{"carol", tormenta.EventDeleted, []tormenta.FieldChange{
+			{Field: "Amount", Old: float64(200), New: nil},
+			{Field: "Number", Old: float64(1), New: nil},
+		}},
+	}
+
+	entries, err := db.AuditTrail(invoice.ID, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Testing audit trail. Didn't expect error [%v]", err)
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("Testing audit trail. Expected %v entries, got %v: %v", len(expected), len(entries), entries)
+	}
+
+	for i, e := range expected {
+		entry := entries[i]
+
+		if entry.Actor != e.actor || entry.Action != e.action {
+			t.Errorf("Testing audit trail entry %v. Expected %s by %s, got %s by %s", i, e.action, e.actor, entry.Action, entry.Actor)
+		}
+
+		if entry.EntityType != "invoice" || entry.EntityID != invoice.ID {
+			t.Errorf("Testing audit trail entry %v. Expected it to be for the invoice, got %s %v", i, entry.EntityType, entry.EntityID)
+		}
+
+		if entry.Created.IsZero() {
+			t.Errorf("Testing audit trail entry %v. Expected a created time", i)
+		}
+
+		if len(entry.Changes) != len(e.changes) {
+			t.Errorf("Testing audit trail entry %v. Expected changes %v, got %v", i, e.changes, entry.Changes)
+			continue
+		}
+
+		for j, change := range e.changes {
+			if entry.Changes[j] != change {
+				t.Errorf("Testing audit trail entry %v. Expected change %v, got %v", i, change, entry.Changes[j])
+			}
+		}
+	}
+
+	// Time range
+	if entries, _ := db.AuditTrail(invoice.ID, afterCreation, time.Time{}); len(entries) != 2 || entries[0].Actor != "bob" {
+		t.Errorf("Testing audit trail from a time. Expected the last 2 entries, got %v", entries)
+	}
+
+	if entries, _ := db.AuditTrail(invoice.ID, time.Time{}, afterCreation); len(entries) != 1 || entries[0].Actor != "alice" {
+		t.Errorf("Testing audit trail to a time. Expected the first entry, got %v", entries)
+	}
+
+	// Auditor - which sees the values before they are serialised
+	if len(auditor.entries) != 4 {
+		t.Fatalf("Testing auditor. Expected 4 entries, got %v", len(auditor.entries))
+	}
+
+	if entry := auditor.entries[1]; entry.Actor != "bob" || len(entry.Changes) != 1 || entry.Changes[0].Old != float64(100) || entry.Changes[0].New != float64(200) {
+		t.Errorf("Testing auditor. Expected the update, got %v", entry)
+	}
+
+	if entry := auditor.entries[3]; entry.Action != tormenta.EventDeleted || entry.ID.IsNil() || entry.Created.IsZero() {
+		t.Errorf("Testing auditor. Expected the deletion, with an ID and created time, got %v", entry)
+	}
+}
+
+func Test_AuditTrail_NotEnabled(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	invoice := testtypes.Invoice{Number: 1}
+	db.SaveWithContext(map[string]interface{}{tormenta.AuditActorKey: "alice"}, &invoice)
+
+	if entries, _ := db.AuditTrail(invoice.ID, time.Time{}, time.Time{}); len(entries) != 0 {
+		t.Errorf("Testing audit trail when not enabled. Expected no entries, got %v", entries)
+	}
+}
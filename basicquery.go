@@ -29,6 +29,9 @@ type basicQuery struct {
 
 	// Is already prepared?
 	prepared bool
+
+	// Profiling for this query, if required
+	step *PlanStep
 }
 
 func (b *basicQuery) prepare() {
@@ -116,11 +119,14 @@ func (b *basicQuery) queryIDs(txn *badger.Txn) (ids idList) {
 	}
 
 	b.reset()
+	b.step.seek(b.seekFrom, b.validTo)
 
 	it := txn.NewIterator(b.getIteratorOptions())
 	defer it.Close()
 
 	for it.Seek(b.seekFrom); b.endIteration(it, len(ids)); it.Next() {
+		b.step.scanned()
+
 		// Skip the first N entities according to the specified offset
 		if b.offsetCounter > 0 {
 			b.offsetCounter--
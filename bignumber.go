@@ -0,0 +1,281 @@
+package tormenta
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// Big numbers.
+// Left to the default encoding, math/big types end up
+// indexed as their string representations, which don't sort numerically.
+// Instead, they are given order preserving binary encodings:
+//
+// big.Int:              sign | magnitude length | magnitude
+// big.Float / big.Rat:  sign | exponent | 128 bit mantissa
+//
+// where zero has its own sign byte, and all bytes after the sign are inverted
+// for negative numbers so that larger magnitudes sort first.
+// Floats and Rats are indexed at 128 bits of precision, which is ample for money
+// but means that Rats which can't be represented exactly are approximated in the index.
+
+const (
+	ErrBigNumberInput = "%v could not be interpreted as a %s"
+
+	bigSignNegativeInf byte = 0x00
+	bigSignNegative    byte = 0x01
+	bigSignZero        byte = 0x02
+	bigSignPositive    byte = 0x03
+	bigSignPositiveInf byte = 0x04
+
+	bigFloatPrecision     = 128
+	bigFloatMantissaBytes = bigFloatPrecision / 8
+)
+
+var (
+	typeBigInt   = reflect.TypeOf(big.Int{})
+	typeBigFloat = reflect.TypeOf(big.Float{})
+	typeBigRat   = reflect.TypeOf(big.Rat{})
+)
+
+func isBigNumberType(t reflect.Type) bool {
+	switch t {
+	case typeBigInt, typeBigFloat, typeBigRat:
+		return true
+	}
+
+	return false
+}
+
+// encodeBigNumber encodes values which are, or point to, big numbers.
+// The second return is false for any other value.
+func encodeBigNumber(value interface{}) ([]byte, bool) {
+	switch n := value.(type) {
+	case *big.Int:
+		return encodeBigInt(n), true
+	case big.Int:
+		return encodeBigInt(&n), true
+	case *big.Float:
+		return encodeBigFloat(n), true
+	case big.Float:
+		return encodeBigFloat(&n), true
+	case *big.Rat:
+		return encodeBigRat(n), true
+	case big.Rat:
+		return encodeBigRat(&n), true
+	}
+
+	return nil, false
+}
+
+// bigNumberInput encodes a user provided value for a search on a big number field.
+// Big number fields accept big numbers, Go numbers and strings.
+func bigNumberInput(value interface{}, t reflect.Type) ([]byte, error) {
+	if t == typeBigInt {
+		i, err := interfaceToBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return encodeBigInt(i), nil
+	}
+
+	f, err := interfaceToBigFloat(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeBigFloat(f), nil
+}
+
+// Encoding
+
+func encodeBigInt(i *big.Int) []byte {
+	if i.Sign() == 0 {
+		return []byte{bigSignZero}
+	}
+
+	magnitude := i.Bytes()
+	b := make([]byte, 5, 5+len(magnitude))
+	b[0] = bigSignPositive
+	binary.BigEndian.PutUint32(b[1:], uint32(len(magnitude)))
+	b = append(b, magnitude...)
+
+	if i.Sign() < 0 {
+		b[0] = bigSignNegative
+		invertBytes(b[1:])
+	}
+
+	return b
+}
+
+func decodeBigInt(b []byte) *big.Int {
+	i := new(big.Int)
+	if len(b) < 5 || b[0] == bigSignZero {
+		return i
+	}
+
+	content := append([]byte{}, b[5:]...)
+	if b[0] == bigSignNegative {
+		invertBytes(content)
+	}
+
+	i.SetBytes(content)
+	if b[0] == bigSignNegative {
+		i.Neg(i)
+	}
+
+	return i
+}
+
+func encodeBigRat(r *big.Rat) []byte {
+	return encodeBigFloat(new(big.Float).SetPrec(bigFloatPrecision).SetRat(r))
+}
+
+func encodeBigFloat(f *big.Float) []byte {
+	if f.IsInf() {
+		if f.Sign() > 0 {
+			return []byte{bigSignPositiveInf}
+		}
+
+		return []byte{bigSignNegativeInf}
+	}
+
+	if f.Sign() == 0 {
+		return []byte{bigSignZero}
+	}
+
+	// f = mantissa * 2^exponent, with 0.5 <= |mantissa| < 1,
+	// so the exponent is compared first, then the mantissa
+	mantissa := new(big.Float)
+	exponent := f.MantExp(mantissa)
+	mantissa.Abs(mantissa)
+	mantissa.SetMantExp(mantissa, bigFloatPrecision)
+	mantissaInt, _ := mantissa.Int(nil)
+
+	b := make([]byte, 5, 5+bigFloatMantissaBytes)
+	b[0] = bigSignPositive
+	binary.BigEndian.PutUint32(b[1:], uint32(exponent)^(1<<31))
+	b = append(b, mantissaInt.FillBytes(make([]byte, bigFloatMantissaBytes))...)
+
+	if f.Sign() < 0 {
+		b[0] = bigSignNegative
+		invertBytes(b[1:])
+	}
+
+	return b
+}
+
+func decodeBigFloat(b []byte) *big.Float {
+	f := new(big.Float).SetPrec(bigFloatPrecision)
+	if len(b) != 5+bigFloatMantissaBytes {
+		return f
+	}
+
+	content := append([]byte{}, b[1:]...)
+	if b[0] == bigSignNegative {
+		invertBytes(content)
+	}
+
+	exponent := int32(binary.BigEndian.Uint32(content[:4]) ^ (1 << 31))
+	mantissa := new(big.Int).SetBytes(content[4:])
+
+	f.SetInt(mantissa)
+	f.SetMantExp(f, int(exponent)-bigFloatPrecision)
+	if b[0] == bigSignNegative {
+		f.Neg(f)
+	}
+
+	return f
+}
+
+func invertBytes(b []byte) {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+}
+
+// Input conversion
+
+func interfaceToBigInt(value interface{}) (*big.Int, error) {
+	switch n := value.(type) {
+	case *big.Int:
+		return n, nil
+	case big.Int:
+		return &n, nil
+	case string:
+		if i, ok := new(big.Int).SetString(n, 10); ok {
+			return i, nil
+		}
+	default:
+		if v := reflect.ValueOf(value); isNumericKind(v.Kind()) {
+			switch v.Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return new(big.Int).SetUint64(v.Uint()), nil
+			case reflect.Float32, reflect.Float64:
+				i, _ := big.NewFloat(v.Float()).Int(nil)
+				return i, nil
+			}
+
+			return big.NewInt(v.Int()), nil
+		}
+	}
+
+	return nil, fmt.Errorf(ErrBigNumberInput, value, "big.Int")
+}
+
+func interfaceToBigFloat(value interface{}) (*big.Float, error) {
+	f := new(big.Float).SetPrec(bigFloatPrecision)
+
+	switch n := value.(type) {
+	case *big.Float:
+		return f.Set(n), nil
+	case big.Float:
+		return f.Set(&n), nil
+	case *big.Rat:
+		return f.SetRat(n), nil
+	case big.Rat:
+		return f.SetRat(&n), nil
+	case *big.Int:
+		return f.SetInt(n), nil
+	case big.Int:
+		return f.SetInt(&n), nil
+	case string:
+		if _, ok := f.SetString(n); ok {
+			return f, nil
+		}
+	default:
+		if v := reflect.ValueOf(value); isNumericKind(v.Kind()) {
+			return f.SetFloat64(numericValueToFloat64(v)), nil
+		}
+	}
+
+	return nil, fmt.Errorf(ErrBigNumberInput, value, "big.Float")
+}
+
+// Sums
+
+func isBigNumberTarget(target interface{}) bool {
+	switch target.(type) {
+	case *big.Int, *big.Float, *big.Rat:
+		return true
+	}
+
+	return false
+}
+
+// quickSumBig adds index content to a big number sum target.
+// The target should be of the same type as the field being summed,
+// except that big.Float and big.Rat targets are interchangeable.
+func quickSumBig(target interface{}, content []byte) {
+	switch t := target.(type) {
+	case *big.Int:
+		t.Add(t, decodeBigInt(content))
+	case *big.Float:
+		t.Add(t, decodeBigFloat(content))
+	case *big.Rat:
+		r, _ := decodeBigFloat(content).Rat(nil)
+		t.Add(t, r)
+	}
+}
@@ -0,0 +1,93 @@
+package tormenta
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func Test_BigInt_Order(t *testing.T) {
+	// In ascending order
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	values := []*big.Int{
+		new(big.Int).Neg(huge), big.NewInt(-70000), big.NewInt(-256), big.NewInt(-255), big.NewInt(-1),
+		big.NewInt(0), big.NewInt(1), big.NewInt(255), big.NewInt(256), big.NewInt(70000), huge,
+	}
+
+	for i := 1; i < len(values); i++ {
+		a, b := encodeBigInt(values[i-1]), encodeBigInt(values[i])
+		if bytes.Compare(a, b) >= 0 {
+			t.Errorf("Testing big int encoding. Expected %v to sort before %v", values[i-1], values[i])
+		}
+	}
+
+	for _, value := range values {
+		if decoded := decodeBigInt(encodeBigInt(value)); decoded.Cmp(value) != 0 {
+			t.Errorf("Testing big int decoding. Expected %v, got %v", value, decoded)
+		}
+	}
+}
+
+func Test_BigFloat_Order(t *testing.T) {
+	// In ascending order
+	values := []string{"-1e100", "-1000.5", "-1000.25", "-1", "-0.001", "0", "0.001", "0.1", "1", "1.01", "1000.25", "1000.5", "1e100"}
+
+	var floats []*big.Float
+	for _, s := range values {
+		f, _ := new(big.Float).SetPrec(bigFloatPrecision).SetString(s)
+		floats = append(floats, f)
+	}
+
+	negativeInf := new(big.Float).SetInf(true)
+	positiveInf := new(big.Float).SetInf(false)
+	floats = append(append([]*big.Float{negativeInf}, floats...), positiveInf)
+
+	for i := 1; i < len(floats); i++ {
+		a, b := encodeBigFloat(floats[i-1]), encodeBigFloat(floats[i])
+		if bytes.Compare(a, b) >= 0 {
+			t.Errorf("Testing big float encoding. Expected %v to sort before %v", floats[i-1], floats[i])
+		}
+	}
+
+	for _, value := range floats[1 : len(floats)-1] {
+		if decoded := decodeBigFloat(encodeBigFloat(value)); decoded.Cmp(value) != 0 {
+			t.Errorf("Testing big float decoding. Expected %v, got %v", value, decoded)
+		}
+	}
+
+	// Rats are encoded as floats
+	if !bytes.Equal(encodeBigRat(big.NewRat(-2001, 2)), encodeBigFloat(floats[2])) {
+		t.Error("Testing big rat encoding. Expected -2001/2 to be encoded the same as -1000.5")
+	}
+}
+
+func Test_CustomIndexInput(t *testing.T) {
+	bigIntType := typeBigInt
+	expectedInt := encodeBigInt(big.NewInt(16))
+	for _, input := range []interface{}{16, int8(16), uint64(16), 16.0, "16", big.NewInt(16), *big.NewInt(16)} {
+		result, err := customIndexInput(input, bigIntType)
+		if err != nil {
+			t.Errorf("Testing big int input %v (%T). Got error %v", input, input, err)
+		}
+
+		if !bytes.Equal(result, expectedInt) {
+			t.Errorf("Testing big int input %v (%T). Expected %v, got %v", input, input, expectedInt, result)
+		}
+	}
+
+	expectedFloat := encodeBigFloat(big.NewFloat(16.5))
+	for _, input := range []interface{}{16.5, float32(16.5), "16.5", big.NewFloat(16.5), big.NewRat(33, 2)} {
+		result, err := customIndexInput(input, typeBigRat)
+		if err != nil {
+			t.Errorf("Testing big float input %v (%T). Got error %v", input, input, err)
+		}
+
+		if !bytes.Equal(result, expectedFloat) {
+			t.Errorf("Testing big float input %v (%T). Expected %v, got %v", input, input, expectedFloat, result)
+		}
+	}
+
+	if _, err := customIndexInput("sixteen", bigIntType); err == nil {
+		t.Error("Testing big int input with an invalid string. Expected an error")
+	}
+}
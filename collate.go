@@ -0,0 +1,73 @@
+package tormenta
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/jpincas/gouuidv6"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Locale aware ordering of strings.
+// Fields tagged `tormenta:"collate=de"` (or any other BCP 47 language tag) get a supplementary
+// index, keyed by the collation sort key of the value rather than the value itself:
+// i:root:indexName#collate:sortKey:entityID -> value
+// OrderBy and Range on the field use this index, so follow the rules of the language,
+// whilst Match, StartsWith etc. continue to use the regular index.
+
+const collateIndex = "collate"
+
+// fieldCollation returns the language for collation specified on the field, if any
+func fieldCollation(field reflect.StructField) string {
+	return tagValue(field, tormentaTagCollate)
+}
+
+func targetCollation(target interface{}, indexName string) string {
+	field, err := structField(target, indexName)
+	if err != nil {
+		return ""
+	}
+
+	return fieldCollation(field)
+}
+
+// Collators are expensive to build, but not safe for concurrent use,
+// so we keep a pool of them for each language
+var collatorPools sync.Map
+
+// collationKey returns the sort key for a string in the given language
+func collationKey(lang, s string) []byte {
+	pool, ok := collatorPools.Load(lang)
+	if !ok {
+		pool, _ = collatorPools.LoadOrStore(lang, &sync.Pool{
+			New: func() interface{} {
+				return collate.New(language.Make(lang))
+			},
+		})
+	}
+
+	c := pool.(*sync.Pool).Get().(*collate.Collator)
+	defer pool.(*sync.Pool).Put(c)
+
+	return c.KeyFromString(&collate.Buffer{}, s)
+}
+
+func getCollationIndex(v reflect.Value, root []byte, id gouuidv6.UUID, indexName []byte, encoding stringEncoding) indexEntry {
+	value := encoding.normalise(v.String())
+
+	return indexEntry{
+		key:   makeIndexKey(root, id, derivedIndexName(indexName, collateIndex), collationKey(encoding.collation, value)),
+		value: []byte(value),
+	}
+}
+
+// orderByIndex is the index used to order the results,
+// which is the collation index if the field has one
+func (q Query) orderByIndex() []byte {
+	if targetCollation(q.target, string(q.orderByIndexName)) != "" {
+		return derivedIndexName(q.orderByIndexName, collateIndex)
+	}
+
+	return q.orderByIndexName
+}
@@ -0,0 +1,31 @@
+package tormenta
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func Test_CollationKey_Concurrent(t *testing.T) {
+	words := []string{"Äpfel", "apfel", "Zebra", "Ölung", "oben"}
+
+	expected := map[string][]byte{}
+	for _, word := range words {
+		expected[word] = collationKey("de", word)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, word := range words {
+				if key := collationKey("de", word); !bytes.Equal(key, expected[word]) {
+					t.Errorf("Testing concurrent collation keys. Key for %s was not as expected", word)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
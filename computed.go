@@ -0,0 +1,77 @@
+package tormenta
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/jpincas/gouuidv6"
+)
+
+// Computed indexes
+// Records can index values derived from their fields, without storing them,
+// by implementing ComputedIndexer.  Each computed value is indexed under its name,
+// encoded according to its kind, just like a field would be.
+// Computed indexes can then be queried by name like any other index,
+// with the kind of the index worked out from the value computed for an empty record.
+
+// ComputedIndexer is implemented by records with computed indexes,
+// e.g. {"Total": r.Qty * r.Price, "EmailDomain": domain(r.Email)}.
+// The values for a given name should always be of the same type,
+// and ComputedIndexes must be safe to call on an empty record.
+type ComputedIndexer interface {
+	ComputedIndexes() map[string]interface{}
+}
+
+func getComputedIndexes(entity Record, root []byte, id gouuidv6.UUID, opts indexOptions) (keys []indexEntry, err error) {
+	computedIndexer, ok := entity.(ComputedIndexer)
+	if !ok {
+		return
+	}
+
+	computed := computedIndexer.ComputedIndexes()
+
+	// Keep the order of the entries stable
+	var names []string
+	for name := range computed {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		indexName := []byte(name)
+		if computed[name] == nil || !indexIf(entity, indexName) {
+			continue
+		}
+
+		content, err := valueIndexContent(reflect.ValueOf(computed[name]), stringEncoding{}, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if content != nil {
+			keys = append(keys, makeIndexEntry(root, id, indexName, content))
+		}
+	}
+
+	return
+}
+
+// computedField describes a computed index of the record type as if it were a field,
+// typed according to the value computed for an empty record
+func computedField(t reflect.Type, name string) (reflect.StructField, bool) {
+	computedIndexer, ok := reflect.New(t).Interface().(ComputedIndexer)
+	if !ok {
+		return reflect.StructField{}, false
+	}
+
+	value, ok := computedIndexer.ComputedIndexes()[name]
+	if !ok || value == nil {
+		return reflect.StructField{}, false
+	}
+
+	return reflect.StructField{
+		Name: name,
+		Type: derefType(reflect.TypeOf(value)),
+	}, true
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"time"
 
 	"github.com/dgraph-io/badger"
 )
@@ -12,6 +13,9 @@ import (
 type DB struct {
 	KV      *badger.DB
 	Options Options
+
+	// Number of active change subscriptions, shared between copies of the DB
+	subscriptions *int32
 }
 
 type Options struct {
@@ -19,6 +23,34 @@ type Options struct {
 	UnserialiseFunc func([]byte, interface{}) error
 	BadgerOptions   badger.Options
 	DebugMode       bool
+
+	// Logger receives debug output as structured log entries, instead of
+	// coloured output on stdout, and query and get errors even when not in debug mode.
+	// A *slog.Logger can be used directly.
+	Logger Logger
+
+	// Metrics receives an event at the end of every Get, Find, Count, Sum, Save and Delete
+	Metrics Metrics
+
+	// UniformNumericIndex encodes all numeric index values (ints, uints and floats of any size)
+	// in the same fixed width, order preserving format, so that they can be queried with
+	// any numeric type.  Changing this on an existing DB requires RebuildIndexes.
+	UniformNumericIndex bool
+
+	// NanosecondTimeIndex indexes time.Time values to the nanosecond, rather than the second,
+	// so that ranges and ordering can distinguish between times within the same second.
+	// Changing this on an existing DB requires RebuildIndexes.
+	NanosecondTimeIndex bool
+
+	// WatchDebounce is how long a watched query waits after a write before it is re-evaluated,
+	// so that a burst of writes results in a single re-evaluation.  Defaults to 100ms.
+	WatchDebounce time.Duration
+
+	// AuditTrail keeps an AuditEntry for every save and delete, see DB.AuditTrail
+	AuditTrail bool
+
+	// Auditor receives an AuditEntry for every save and delete
+	Auditor Auditor
 }
 
 var DefaultOptions = Options{
@@ -98,8 +130,9 @@ func (db DB) Close() error {
 
 func openDB(badgerDB *badger.DB, options Options) (*DB, error) {
 	return &DB{
-		KV:      badgerDB,
-		Options: options,
+		KV:            badgerDB,
+		Options:       options,
+		subscriptions: new(int32),
 	}, nil
 }
 
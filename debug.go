@@ -9,10 +9,22 @@ import (
 	"github.com/wsxiaoys/terminal/color"
 )
 
-func debugLogGet(target interface{}, start time.Time, noResults int, err error, ids ...gouuidv6.UUID) {
-	if err != nil {
-		msg := color.Sprintf("@Returned error: %s", err)
-		fmt.Println(msg)
+// Logger is the interface for structured debug output.
+// It is satisfied by *slog.Logger from the standard library.
+// If no logger is specified in the options, debug output goes to stdout in colour.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+func (db DB) debugLogGet(target interface{}, start time.Time, noResults int, err error, ids ...gouuidv6.UUID) {
+	logger := db.Options.Logger
+
+	// Errors always go to the logger if there is one, so that they are seen in production,
+	// but everything else is only logged in debug mode
+	if !db.Options.DebugMode && (logger == nil || err == nil) {
 		return
 	}
 
@@ -24,6 +36,22 @@ func debugLogGet(target interface{}, start time.Time, noResults int, err error,
 	}
 	idsOutput := strings.Join(idsStrings, ",")
 
+	if logger != nil {
+		if err != nil {
+			logger.Error("tormenta get", "entity", string(entityName), "ids", idsOutput, "error", err)
+			return
+		}
+
+		logger.Debug("tormenta get", "entity", string(entityName), "ids", idsOutput, "results", noResults, "duration", time.Since(start))
+		return
+	}
+
+	if err != nil {
+		msg := color.Sprintf("@Returned error: %s", err)
+		fmt.Println(msg)
+		return
+	}
+
 	msg := color.Sprintf(
 		"@{!}GET@{|} @y[%s | %s]@{|} returned @c%v@{|} result(s) in @g%s",
 		entityName,
@@ -35,9 +63,22 @@ func debugLogGet(target interface{}, start time.Time, noResults int, err error,
 }
 
 func (q Query) debugLog(start time.Time, noResults int, err error) {
+	logger := q.db.Options.Logger
+
 	// To log, we either need to be either in global debug mode,
-	// or the debug flag for this query needs to be set to true
-	if !q.db.Options.DebugMode && !q.debug {
+	// or the debug flag for this query needs to be set to true.
+	// Errors always go to the logger if there is one, so that they are seen in production.
+	if !q.db.Options.DebugMode && !q.debug && (logger == nil || err == nil) {
+		return
+	}
+
+	if logger != nil {
+		if err != nil {
+			logger.Error("tormenta query", "query", q.String(), "error", err)
+			return
+		}
+
+		logger.Debug("tormenta query", "query", q.String(), "results", noResults, "duration", time.Since(start))
 		return
 	}
 
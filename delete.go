@@ -2,6 +2,7 @@ package tormenta
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/dgraph-io/badger"
 	"github.com/jpincas/gouuidv6"
@@ -12,33 +13,75 @@ const (
 )
 
 func (db DB) Delete(entity Record, ids ...gouuidv6.UUID) error {
+	return db.DeleteWithContext(entity, noCTX, ids...)
+}
+
+// DeleteWithContext deletes an entity in the same way as Delete,
+// with a context giving e.g. the actor for the audit trail
+func (db DB) DeleteWithContext(entity Record, ctx map[string]interface{}, ids ...gouuidv6.UUID) error {
+	t := time.Now()
+
 	// If a separate entity ID has been specified then use it
 	if len(ids) > 0 {
 		entity.SetID(ids[0])
 	}
 
-	// First lets try to get the entity,
-	// Its a good sanity check to make sure it really exists,
-	// but more importantly we're going to need to deindex it,
-	// so we'll need it current state
-	if found, err := db.Get(entity); err != nil {
-		return err
-	} else if !found {
-		return fmt.Errorf(ErrRecordNotFound, entity.GetID())
-	}
-
+	var auditEntry *AuditEntry
 	err := db.KV.Update(func(txn *badger.Txn) error {
+		if err := checkNotView(entity); err != nil {
+			return err
+		}
+
+		// First lets try to get the entity,
+		// Its a good sanity check to make sure it really exists,
+		// but more importantly we're going to need to deindex it,
+		// so we'll need it current state
+		if found, err := db.get(txn, entity, noCTX); err != nil {
+			return err
+		} else if !found {
+			return fmt.Errorf(ErrRecordNotFound, entity.GetID())
+		}
+
+		if err := db.savePrevious(txn, KeyRoot(entity), entity.GetID()); err != nil {
+			return err
+		}
+
+		if err := db.saveHistory(txn, entity, KeyRoot(entity), entity.GetID()); err != nil {
+			return err
+		}
+
+		if db.auditing() {
+			var err error
+			auditEntry, err = db.auditEntry(ctx, EventDeleted, KeyRoot(entity), entity.GetID(), removeSkippedFields(recordValue(entity)), nil)
+			if err != nil {
+				return err
+			}
+
+			if err := db.saveAuditEntry(txn, auditEntry); err != nil {
+				return err
+			}
+		}
+
 		if err := deleteRecord(txn, entity); err != nil {
 			return err
 		}
 
-		if err := deIndex(txn, entity); err != nil {
+		if err := db.deIndex(txn, entity); err != nil {
 			return err
 		}
 
 		return nil
 	})
 
+	var n int
+	if err == nil {
+		n = 1
+		if auditEntry != nil {
+			db.reportAudit([]*AuditEntry{auditEntry})
+		}
+	}
+
+	db.observe(OpDelete, KeyRoot(entity), t, n, err)
 	return err
 }
 
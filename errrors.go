@@ -5,6 +5,8 @@ const (
 	ErrNilInputMatchIndexQuery   = "Nil is not a valid input for an exact match search"
 	ErrNilInputsRangeIndexQuery  = "Nil from both ends of the range is not a valid input for an index range search"
 	ErrBlankInputStartsWithQuery = "Blank string is not valid input for 'starts with' query"
+	ErrNoWordsContainsQuery      = "At least one content word is required for a 'contains' query"
+	ErrFieldNotSplit             = "Field %s needs to be tagged 'split' for a 'contains all/any' query"
 	ErrFieldCouldNotBeFound      = "Field %s could not be found"
 	ErrIndexTypeBool             = "%v could not be interpreted as true/false"
 )
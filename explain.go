@@ -0,0 +1,204 @@
+package tormenta
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Plan stages
+const (
+	PlanStageFilter = "filter"
+	PlanStageAll    = "all"
+	PlanStageOrder  = "order"
+	PlanStageSum    = "sum"
+	PlanStageFetch  = "fetch"
+	PlanStageSearch = "search"
+)
+
+// Plan is the profile of an executed query - one step for each index pass or record fetch,
+// plus the aggregated totals
+type Plan struct {
+	Query  string
+	Steps  []*PlanStep
+	Totals PlanTotals
+}
+
+// PlanStep records what a single stage of query execution did
+type PlanStep struct {
+	Stage    string
+	Strategy string
+	Index    string
+	Filter   string
+
+	// The keys the iteration started from and was bounded by
+	SeekFrom, ValidTo []byte
+
+	// Estimated number of keys, as used by the planner to order filters
+	Estimate int
+
+	KeysScanned    int
+	IDsEmitted     int
+	RecordsFetched int
+	Duration       time.Duration
+}
+
+// PlanTotals aggregates all the steps of a Plan.
+// It contains no raw keys, so is suitable for logging in production.
+type PlanTotals struct {
+	Steps          int
+	KeysScanned    int
+	IDsEmitted     int
+	RecordsFetched int
+	Results        int
+	Duration       time.Duration
+}
+
+// Explain runs the query and returns the plan that was used,
+// along with the keys scanned, IDs emitted, records fetched and time spent at each step.
+// Results are set on the target as with Run.
+func (q *Query) Explain() (Plan, error) {
+	q.profile = &Plan{Query: q.String()}
+	defer func() { q.profile = nil }()
+
+	t := time.Now()
+	n, err := q.execute()
+	if err != nil {
+		return Plan{}, err
+	}
+
+	q.profile.totalUp(n, time.Since(t))
+	return *q.profile, nil
+}
+
+func (p *Plan) addStep(step PlanStep) *PlanStep {
+	if p == nil {
+		return nil
+	}
+
+	p.Steps = append(p.Steps, &step)
+	return &step
+}
+
+func (p *Plan) addFilterStep(f filter, strategy string, estimate int) *PlanStep {
+	// Check up front, to avoid building the filter description
+	// when the query is not being profiled
+	if p == nil {
+		return nil
+	}
+
+	return p.addStep(PlanStep{
+		Stage:    PlanStageFilter,
+		Strategy: strategy,
+		Index:    string(f.indexName),
+		Filter:   f.String(),
+		SeekFrom: f.seekFrom,
+		ValidTo:  f.validTo,
+		Estimate: estimate,
+	})
+}
+
+func (p *Plan) totalUp(results int, duration time.Duration) {
+	p.Totals = PlanTotals{
+		Steps:    len(p.Steps),
+		Results:  results,
+		Duration: duration,
+	}
+
+	for _, step := range p.Steps {
+		p.Totals.KeysScanned += step.KeysScanned
+		p.Totals.IDsEmitted += step.IDsEmitted
+		p.Totals.RecordsFetched += step.RecordsFetched
+	}
+}
+
+func (p Plan) String() string {
+	lines := []string{p.Query}
+
+	for i, step := range p.Steps {
+		lines = append(lines, fmt.Sprintf("  %v. %s", i+1, step))
+	}
+
+	lines = append(lines, fmt.Sprintf("  %s", p.Totals))
+	return strings.Join(lines, "\n")
+}
+
+func (s PlanStep) String() string {
+	components := []string{s.Stage}
+
+	if s.Strategy != "" {
+		components = append(components, s.Strategy)
+	}
+
+	if s.Filter != "" {
+		components = append(components, fmt.Sprintf("[%s]", s.Filter))
+	} else if s.Index != "" {
+		components = append(components, fmt.Sprintf("[%s]", s.Index))
+	}
+
+	if s.Stage == PlanStageFetch {
+		components = append(components, fmt.Sprintf("records=%v", s.RecordsFetched))
+	} else {
+		components = append(components,
+			fmt.Sprintf("est=%v", s.Estimate),
+			fmt.Sprintf("scanned=%v", s.KeysScanned),
+			fmt.Sprintf("ids=%v", s.IDsEmitted),
+		)
+	}
+
+	components = append(components, fmt.Sprintf("in %s", s.Duration))
+	return strings.Join(components, " ")
+}
+
+func (t PlanTotals) String() string {
+	return fmt.Sprintf(
+		"total: scanned=%v ids=%v records=%v results=%v in %s",
+		t.KeysScanned, t.IDsEmitted, t.RecordsFetched, t.Results, t.Duration,
+	)
+}
+
+// Step recording helpers.  These are all safe to call on a nil step,
+// which is what we have when the query is not being profiled
+
+func (s *PlanStep) seek(seekFrom, validTo []byte) {
+	if s == nil {
+		return
+	}
+
+	s.SeekFrom = seekFrom
+	s.ValidTo = validTo
+}
+
+func (s *PlanStep) scanned() {
+	if s == nil {
+		return
+	}
+
+	s.KeysScanned++
+}
+
+func (s *PlanStep) scannedKeys(n int) {
+	if s == nil {
+		return
+	}
+
+	s.KeysScanned += n
+}
+
+func (s *PlanStep) finish(start time.Time, ids int) {
+	if s == nil {
+		return
+	}
+
+	s.IDsEmitted = ids
+	s.Duration = time.Since(start)
+}
+
+func (s *PlanStep) fetched(start time.Time, records int) {
+	if s == nil {
+		return
+	}
+
+	s.RecordsFetched = records
+	s.Duration = time.Since(start)
+}
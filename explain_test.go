@@ -0,0 +1,32 @@
+package tormenta
+
+import "testing"
+
+type explainStruct struct {
+	Model
+
+	IntField int
+}
+
+func Test_Explain_ClearsProfile(t *testing.T) {
+	db, _ := OpenTestWithOptions("data/tests", DefaultOptions)
+	defer db.Close()
+
+	db.Save(&explainStruct{IntField: 1})
+
+	var results []explainStruct
+	q := db.Find(&results).Match("IntField", 1)
+	if _, err := q.Explain(); err != nil {
+		t.Fatalf("Testing explain. Got error %v", err)
+	}
+
+	if q.profile != nil {
+		t.Error("Testing explain. Expected the profile to be cleared afterwards")
+	}
+
+	// Later runs of the same query are not profiled
+	q.Run()
+	if q.profile != nil {
+		t.Error("Testing run after explain. Expected the query not to be profiled")
+	}
+}
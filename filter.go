@@ -42,20 +42,52 @@ type filter struct {
 
 	indexKind reflect.Kind
 
+	// The type of the field being searched, so that input
+	// can be converted for big number and IndexValuer fields
+	indexType reflect.Type
+
+	// How string input is encoded for this index
+	stringEncoding stringEncoding
+
+	// Copied from the DB options: how are numbers and times indexed?
+	indexOptions indexOptions
+
+	// Is the index sparse, i.e. without zero values?
+	sparse bool
+
 	// Is this a 'starts with' index query
 	isStartsWithQuery bool
 
+	// Words for a 'contains' query on a split index,
+	// matching records with all of the words, or any of them
+	words        []string
+	matchAnyWord bool
+	wordFilters  []filter
+
+	// Substring for a 'contains' query on a trigram index
+	substring string
+
+	// Zero value check for IsZero/NotZero queries
+	zero *zeroCheck
+
 	// Ranges and comparision key
 	seekFrom, validTo, compareTo []byte
 
 	// Is already prepared?
 	prepared bool
+
+	// Profiling for this filter, if required
+	step *PlanStep
 }
 
 func (f filter) isIndexRangeSearch() bool {
 	return f.start != f.end && !f.isStartsWithQuery
 }
 
+func (f filter) isWordsSearch() bool {
+	return len(f.words) > 0
+}
+
 func (f filter) isExactIndexMatchSearch() bool {
 	return f.start == f.end && f.start != nil && f.end != nil
 }
@@ -67,6 +99,17 @@ func (f *filter) prepare() error {
 		f.reverse = false
 	}
 
+	// A 'contains' query on a split index is made up of an exact match for each word
+	if f.isWordsSearch() {
+		return f.prepareWordFilters()
+	}
+
+	// 'Contains' queries on a trigram index and zero value queries need no ranges
+	if f.isContainsQuery() || f.isZeroQuery() {
+		f.prepared = true
+		return nil
+	}
+
 	f.setFromToIfEmpty()
 	err := f.setRanges()
 	if err != nil {
@@ -129,16 +172,20 @@ func (f *filter) setRanges() error {
 		f.from = tempTo
 	}
 
-	startBytes, err := interfaceToBytesWithOverride(f.start, f.indexKind)
+	startBytes, err := f.encodeInput(f.start)
 	if err != nil {
 		return err
 	}
 
-	endBytes, err := interfaceToBytesWithOverride(f.end, f.indexKind)
+	endBytes, err := f.encodeInput(f.end)
 	if err != nil {
 		return err
 	}
 
+	if err := f.checkSparse(startBytes, endBytes); err != nil {
+		return err
+	}
+
 	if f.isExactIndexMatchSearch() {
 		// For index searches with exact match
 		seekFrom = newIndexMatchKey(f.keyRoot, f.indexName, startBytes, f.from).bytes()
@@ -214,12 +261,27 @@ func (f *filter) queryIDs(txn *badger.Txn) (ids idList, err error) {
 		}
 	}
 
+	if f.isWordsSearch() {
+		return f.queryWordIDs(txn)
+	}
+
+	if f.isContainsQuery() {
+		return f.queryContainsIDs(txn)
+	}
+
+	if f.isZeroQuery() {
+		return f.queryZeroIDs(txn)
+	}
+
 	f.reset()
+	f.step.seek(f.seekFrom, f.validTo)
 
 	it := txn.NewIterator(f.getIteratorOptions())
 	defer it.Close()
 
 	for it.Seek(f.seekFrom); f.endIteration(it, ids.length()); it.Next() {
+		f.step.scanned()
+
 		// If this is a 'range index' type Query
 		// that ALSO has a date range, the procedure is a little more complicated
 		// compared to an exact index match.
@@ -245,6 +307,96 @@ func (f *filter) queryIDs(txn *badger.Txn) (ids idList, err error) {
 	return
 }
 
+// 'Contains' queries on split indexes.
+// Each word is looked up with its own exact match filter,
+// and the resulting ID lists are intersected (all words) or unioned (any word)
+
+func (f *filter) prepareWordFilters() error {
+	f.wordFilters = nil
+
+	for _, word := range f.words {
+		wordFilter := filter{
+			from:      f.from,
+			to:        f.to,
+			reverse:   f.reverse,
+			keyRoot:   f.keyRoot,
+			start:     word,
+			end:       word,
+			indexName: f.indexName,
+			indexKind: f.indexKind,
+
+			stringEncoding: f.stringEncoding,
+			indexOptions:   f.indexOptions,
+		}
+
+		if err := wordFilter.prepare(); err != nil {
+			return err
+		}
+
+		f.wordFilters = append(f.wordFilters, wordFilter)
+	}
+
+	f.prepared = true
+	return nil
+}
+
+func (f filter) combineWordIDs(listsOfIDs ...idList) idList {
+	if f.matchAnyWord {
+		return union(listsOfIDs...)
+	}
+
+	return intersection(listsOfIDs...)
+}
+
+func (f *filter) queryWordIDs(txn *badger.Txn) (idList, error) {
+	var listsOfIDs []idList
+
+	for _, wordFilter := range f.wordFilters {
+		wordFilter.step = f.step
+		ids, err := wordFilter.queryIDs(txn)
+		if err != nil {
+			return idList{}, err
+		}
+
+		// No point looking any further if one of the words is missing
+		if len(ids) == 0 && !f.matchAnyWord {
+			return idList{}, nil
+		}
+
+		listsOfIDs = append(listsOfIDs, ids)
+	}
+
+	// Combining the lists loses the ordering,
+	// so we sort before applying limit and offset
+	ids := f.combineWordIDs(listsOfIDs...)
+	ids.sort(f.reverse)
+
+	return ids.limitOffset(f.limit, f.offset), nil
+}
+
+// encodeInput encodes a user provided value in the same way as the index
+func (f filter) encodeInput(value interface{}) ([]byte, error) {
+	if isCustomIndexType(f.indexType) {
+		return customIndexInput(value, f.indexType)
+	}
+
+	// Values of interface fields, e.g. map[string]interface{}, are indexed by their actual kind
+	indexKind := f.indexKind
+	if indexKind == reflect.Interface {
+		indexKind, value = interfaceInput(value)
+	}
+
+	if f.indexOptions.uniformNumeric && isNumericKind(indexKind) {
+		return uniformNumericInput(value)
+	}
+
+	if t, ok := asTime(value); ok && f.indexOptions.nanosecondTime {
+		return nanosecondTimeBytes(t), nil
+	}
+
+	return interfaceToBytesWithOverride(value, indexKind, f.stringEncoding)
+}
+
 // Helpers
 
 func toIndexName(s string) []byte {
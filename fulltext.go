@@ -0,0 +1,302 @@
+package tormenta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Full text indexing
+// Fields tagged `tormenta:"fulltext"` (or `tormenta:"fulltext=en"` to specify a language)
+// are tokenised and each distinct term is indexed:
+// i:root:indexName:term:entityID -> term frequency | document length
+// The length of each document is also indexed separately so that we can work
+// out the number of documents and the average document length when scoring:
+// i:root:indexName#length:documentLength:entityID
+
+const (
+	// Language used for full text fields that don't specify one
+	DefaultFullTextLanguage = "en"
+
+	fullTextLengthIndex = "length"
+
+	// BM25 parameters
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	ErrFieldNotFullText = "Field %s is not a full text field"
+	ErrNoSearchTerms    = "Search text contains no searchable terms"
+)
+
+// Analyzer specifies how text is broken down into terms for a particular language
+type Analyzer struct {
+	StopWords map[string]bool
+	Stem      func(string) string
+
+	// Remove diacritics before tokenising, set by tagging the field `tormenta:"fold"`
+	Fold bool
+}
+
+// FullTextAnalyzers are the languages available to full text fields.
+// Add to or modify this map to configure stop words and stemming.
+var FullTextAnalyzers = map[string]Analyzer{
+	"en": {
+		StopWords: stopWordsEnglish,
+		Stem:      stemEnglish,
+	},
+	"simple": {},
+}
+
+var stopWordsEnglish = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "if": true, "in": true,
+	"into": true, "is": true, "it": true, "no": true, "not": true, "of": true,
+	"on": true, "or": true, "such": true, "that": true, "the": true, "their": true,
+	"then": true, "there": true, "these": true, "they": true, "this": true, "to": true,
+	"was": true, "will": true, "with": true,
+}
+
+// Tokenize breaks text down into terms - splitting on anything that isn't
+// a letter or number, lower casing, removing stop words and stemming
+func (a Analyzer) Tokenize(s string) (terms []string) {
+	if a.Fold {
+		s = foldString(s)
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	for _, word := range words {
+		if a.StopWords[word] {
+			continue
+		}
+
+		if a.Stem != nil {
+			word = a.Stem(word)
+		}
+
+		terms = append(terms, word)
+	}
+
+	return
+}
+
+func fullTextAnalyzer(field reflect.StructField) Analyzer {
+	language := tagValue(field, tormentaTagFullText)
+	if language == "" {
+		language = DefaultFullTextLanguage
+	}
+
+	analyzer := FullTextAnalyzers[language]
+	analyzer.Fold = isTaggedWith(field, tormentaTagFold)
+	return analyzer
+}
+
+// stemEnglish is a light suffix-stripping stemmer, mainly to deal with plurals
+// and the most common verb endings
+func stemEnglish(word string) string {
+	if len(word) <= 3 {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "sses"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "ies"):
+		word = strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "ss"), strings.HasSuffix(word, "us"), strings.HasSuffix(word, "is"):
+		// Not a plural
+	case strings.HasSuffix(word, "s"):
+		word = strings.TrimSuffix(word, "s")
+	}
+
+	for _, suffix := range []string{"ingly", "edly", "ing", "ed", "ly"} {
+		stem := strings.TrimSuffix(word, suffix)
+		if stem == word || len(stem) < 3 || !strings.ContainsAny(stem, "aeiouy") {
+			continue
+		}
+
+		// Undouble the final consonant, e.g. running -> run
+		n := len(stem)
+		if stem[n-1] == stem[n-2] && !strings.ContainsAny(stem[n-1:], "aeiouylsz") {
+			stem = stem[:n-1]
+		}
+
+		return stem
+	}
+
+	return word
+}
+
+// Indexing
+
+func getFullTextIndexes(v reflect.Value, root []byte, id gouuidv6.UUID, indexName []byte, analyzer Analyzer) (keys []indexEntry) {
+	terms := analyzer.Tokenize(v.String())
+
+	frequencies := map[string]uint32{}
+	for _, term := range terms {
+		frequencies[term]++
+	}
+
+	for term, frequency := range frequencies {
+		keys = append(keys, indexEntry{
+			key:   makeIndexKey(root, id, indexName, term),
+			value: encodeTermFrequency(frequency, uint32(len(terms))),
+		})
+	}
+
+	keys = append(keys, makeIndexEntry(root, id, derivedIndexName(indexName, fullTextLengthIndex), uint32(len(terms))))
+	return
+}
+
+func encodeTermFrequency(frequency, documentLength uint32) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b[:4], frequency)
+	binary.BigEndian.PutUint32(b[4:], documentLength)
+	return b
+}
+
+func decodeTermFrequency(b []byte) (frequency, documentLength uint32) {
+	if len(b) != 8 {
+		return
+	}
+
+	return binary.BigEndian.Uint32(b[:4]), binary.BigEndian.Uint32(b[4:])
+}
+
+// Searching
+
+type fullTextSearch struct {
+	indexName []byte
+	terms     []string
+}
+
+// rank scores every document containing at least one of the search terms using BM25
+// and returns the IDs of those documents, best match first
+func (s fullTextSearch) rank(txn *badger.Txn, keyRoot []byte, from, to gouuidv6.UUID, step *PlanStep) (idList, error) {
+	// Collection statistics from the length index
+	var noDocuments, totalLength float64
+
+	lengthPrefix := newIndexKey(keyRoot, derivedIndexName(s.indexName, fullTextLengthIndex), nil).bytes()
+	err := iteratePrefix(txn, lengthPrefix, false, func(item *badger.Item) error {
+		step.scanned()
+
+		var documentLength uint32
+		binary.Read(bytes.NewBuffer(indexContent(item.Key(), lengthPrefix)), binary.BigEndian, &documentLength)
+
+		noDocuments++
+		totalLength += float64(documentLength)
+		return nil
+	})
+
+	if err != nil || noDocuments == 0 {
+		return idList{}, err
+	}
+
+	averageLength := totalLength / noDocuments
+
+	// Score each term in turn
+	scores := map[gouuidv6.UUID]float64{}
+	for _, term := range uniqueStrings(s.terms) {
+		type posting struct {
+			id                        gouuidv6.UUID
+			frequency, documentLength uint32
+		}
+
+		var postings []posting
+
+		termPrefix := append(newIndexMatchKey(keyRoot, s.indexName, interfaceToBytes(term)).bytes(), []byte(keySeparator)...)
+		err := iteratePrefix(txn, termPrefix, true, func(item *badger.Item) error {
+			step.scanned()
+
+			return item.Value(func(val []byte) error {
+				frequency, documentLength := decodeTermFrequency(val)
+				postings = append(postings, posting{extractID(item.Key()), frequency, documentLength})
+				return nil
+			})
+		})
+
+		if err != nil {
+			return idList{}, err
+		}
+
+		df := float64(len(postings))
+		idf := math.Log(1 + (noDocuments-df+0.5)/(df+0.5))
+
+		for _, p := range postings {
+			if keyIsOutsideDateRange(p.id, from, to) {
+				continue
+			}
+
+			tf := float64(p.frequency)
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(p.documentLength)/averageLength)
+			scores[p.id] += idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+	}
+
+	return rankedIDs(scores), nil
+}
+
+// Search adds a full text search on a field tagged `tormenta:"fulltext"`.
+// Unless the query is ordered by another index, results are ranked by relevance (BM25), best match first.
+func (q *Query) Search(indexName string, text string) *Query {
+	field, err := structField(q.target, indexName)
+	if err != nil {
+		q.err = err
+		return q
+	}
+
+	if !isTaggedWith(field, tormentaTagFullText) {
+		q.err = fmt.Errorf(ErrFieldNotFullText, indexName)
+		return q
+	}
+
+	terms := fullTextAnalyzer(field).Tokenize(text)
+	if len(terms) == 0 {
+		q.err = errors.New(ErrNoSearchTerms)
+		return q
+	}
+
+	return q.setRanker(fullTextSearch{
+		indexName: toIndexName(indexName),
+		terms:     terms,
+	})
+}
+
+func (s fullTextSearch) describe() PlanStep {
+	return PlanStep{
+		Stage:    PlanStageSearch,
+		Strategy: PlanStrategyScan,
+		Index:    string(s.indexName),
+		Filter:   s.String(),
+	}
+}
+
+func (s fullTextSearch) component() queryComponent {
+	return queryComponent{queryStringSearch, s}
+}
+
+func (s fullTextSearch) String() string {
+	return fmt.Sprintf("%s%s%s", s.indexName, whereValueSeparator, strings.Join(s.terms, " "))
+}
+
+func uniqueStrings(ss []string) (result []string) {
+	seen := map[string]bool{}
+	for _, s := range ss {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+
+	return
+}
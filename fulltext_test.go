@@ -0,0 +1,130 @@
+package tormenta_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_Analyzer_Tokenize(t *testing.T) {
+	analyzer := tormenta.FullTextAnalyzers["en"]
+
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{"", nil},
+		{"Sweater", []string{"sweater"}},
+		{"Red, wool SWEATERS!", []string{"red", "wool", "sweater"}},
+		{"the cat is on the mat", []string{"cat", "mat"}},
+		{"running jumped quickly", []string{"run", "jump", "quick"}},
+		{"ponies and classes", []string{"pony", "class"}},
+		{"café-crème naïve", []string{"café", "crème", "naïve"}},
+		{"order #A123/45", []string{"order", "a123", "45"}},
+	}
+
+	for _, testCase := range testCases {
+		result := analyzer.Tokenize(testCase.input)
+		if fmt.Sprint(result) != fmt.Sprint(testCase.expected) {
+			t.Errorf("Testing tokenizing '%s'. Expected %v, got %v", testCase.input, testCase.expected, result)
+		}
+	}
+}
+
+func Test_Search(t *testing.T) {
+	texts := []string{
+		"Red wool sweater",
+		"A red sweater made of wool, the reddest of red sweaters",
+		"Blue cotton shirt",
+		"Green wool scarf",
+		"Red cotton socks",
+	}
+
+	var fullStructs []tormenta.Record
+	for i, text := range texts {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:      i,
+			FullTextField: text,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName      string
+		search        string
+		expected      []int
+		expectedError error
+	}{
+		{"single term", "sweater", []int{1, 0}, nil},
+		{"case and plurals", "SWEATERS", []int{1, 0}, nil},
+		{"multiple terms - ranked", "red wool", []int{0, 1, 3, 4}, nil},
+		{"no matches", "trousers", []int{}, nil},
+		{"only stop words", "the of a", []int{}, errors.New(tormenta.ErrNoSearchTerms)},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		n, err := db.Find(&results).Search("FullTextField", testCase.search).Run()
+
+		if testCase.expectedError != nil && err == nil {
+			t.Errorf("Testing %s. Expected error [%v] but got none", testCase.testName, testCase.expectedError)
+		}
+
+		if testCase.expectedError == nil && err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != len(testCase.expected) {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, len(testCase.expected), n)
+			continue
+		}
+
+		for i, result := range results {
+			if result.IntField != testCase.expected[i] {
+				t.Errorf("Testing %s. Expected result %v to be %v, got %v", testCase.testName, i, testCase.expected[i], result.IntField)
+			}
+		}
+	}
+
+	// Combined with a filter, limited
+	results := []testtypes.FullStruct{}
+	n, err := db.Find(&results).Search("FullTextField", "red").Range("IntField", 1, 4).Limit(1).Run()
+	if err != nil {
+		t.Errorf("Testing search with filter. Got error %v", err)
+	}
+
+	if n != 1 || results[0].IntField != 1 {
+		t.Errorf("Testing search with filter. Expected record 1 only, got %v", results)
+	}
+
+	// Non full text field
+	if _, err := db.Find(&results).Search("StringField", "red").Run(); err == nil {
+		t.Error("Testing search on non full text field. Expected error but got none")
+	}
+}
+
+func Test_Search_Reindex(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	fullStruct := testtypes.FullStruct{FullTextField: "red wool sweater"}
+	db.Save(&fullStruct)
+
+	fullStruct.FullTextField = "blue cotton shirt"
+	db.Save(&fullStruct)
+
+	results := []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Search("FullTextField", "sweater").Count(); n != 0 {
+		t.Errorf("Testing search after update. Old terms should have been deindexed, but got %v results", n)
+	}
+
+	if n, _ := db.Find(&results).Search("FullTextField", "shirts").Count(); n != 1 {
+		t.Errorf("Testing search after update. Expected 1 result for new terms, got %v", n)
+	}
+}
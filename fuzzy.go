@@ -0,0 +1,303 @@
+package tormenta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Fuzzy matching
+// Finds string index values within a given number of edits (Levenshtein distance) of a search term.
+// Without any extra indexing, this means checking every value in the field's index.
+// Fields tagged `tormenta:"trigram"` also have every 3 letter sequence of the value indexed:
+// i:root:indexName#trigram:trigram:entityID -> value
+// which lets us go straight to the values that share enough trigrams with the search term
+// to possibly be within the edit distance, and only check those.
+
+const (
+	fuzzyTrigramIndex = "trigram"
+	trigramLength     = 3
+	trigramPadding    = " "
+
+	ErrFieldNotString    = "Field %s is not a string field"
+	ErrBlankInputFuzzy   = "Blank string is not valid input for a fuzzy search"
+	ErrNegativeFuzzyEdit = "Maximum number of edits for a fuzzy search cannot be negative"
+)
+
+// Indexing
+
+// trigrams breaks a string down into its distinct 3 letter sequences.
+// The string is padded at either end so that the start and end of the string
+// are well represented
+func trigrams(s string) []string {
+	padding := strings.Repeat(trigramPadding, trigramLength-1)
+	return splitTrigrams(padding + s + padding)
+}
+
+func splitTrigrams(s string) (results []string) {
+	runes := []rune(s)
+
+	for i := 0; i+trigramLength <= len(runes); i++ {
+		results = append(results, string(runes[i:i+trigramLength]))
+	}
+
+	return uniqueStrings(results)
+}
+
+func getTrigramIndexes(v reflect.Value, root []byte, id gouuidv6.UUID, indexName []byte, encoding stringEncoding) (keys []indexEntry) {
+	value := encoding.normalise(v.String())
+
+	for _, trigram := range trigrams(value) {
+		keys = append(keys, indexEntry{
+			key:   makeIndexKey(root, id, derivedIndexName(indexName, fuzzyTrigramIndex), []byte(trigram)),
+			value: []byte(value),
+		})
+	}
+
+	return
+}
+
+// Searching
+
+type fuzzySearch struct {
+	indexName []byte
+	term      string
+	maxEdits  int
+
+	// Can the trigram index be used to narrow down the candidates?
+	useTrigrams bool
+}
+
+// Fuzzy adds a typo tolerant search on a string field, matching values within maxEdits
+// insertions, deletions or substitutions of the term.
+// Unless the query is ordered by another index, results are ranked by distance, closest first.
+// Tag the field `tormenta:"trigram"` to avoid checking every value in the index.
+func (q *Query) Fuzzy(indexName string, term string, maxEdits int) *Query {
+	if term == "" {
+		q.err = errors.New(ErrBlankInputFuzzy)
+		return q
+	}
+
+	if maxEdits < 0 {
+		q.err = errors.New(ErrNegativeFuzzyEdit)
+		return q
+	}
+
+	field, err := structField(q.target, indexName)
+	if err != nil {
+		q.err = err
+		return q
+	}
+
+	if field.Type.Kind() != reflect.String {
+		q.err = fmt.Errorf(ErrFieldNotString, indexName)
+		return q
+	}
+
+	s := fuzzySearch{
+		indexName: toIndexName(indexName),
+		term:      fieldStringEncoding(field).normalise(term),
+		maxEdits:  maxEdits,
+	}
+
+	s.useTrigrams = isTaggedWith(field, tormentaTagTrigram) && s.minSharedTrigrams() > 0
+	return q.setRanker(s)
+}
+
+// minSharedTrigrams is the fewest trigrams of the term that a value within the edit
+// distance can have.  Each edit can destroy at most 3 of them.
+func (s fuzzySearch) minSharedTrigrams() int {
+	return len(trigrams(s.term)) - s.maxEdits*trigramLength
+}
+
+func (s fuzzySearch) rank(txn *badger.Txn, keyRoot []byte, from, to gouuidv6.UUID, step *PlanStep) (idList, error) {
+	distances := map[gouuidv6.UUID]int{}
+
+	check := func(id gouuidv6.UUID, value []byte) {
+		if keyIsOutsideDateRange(id, from, to) {
+			return
+		}
+
+		if distance, ok := s.distance(string(value)); ok {
+			if existing, found := distances[id]; !found || distance < existing {
+				distances[id] = distance
+			}
+		}
+	}
+
+	var err error
+	if s.useTrigrams {
+		err = s.trigramCandidates(txn, keyRoot, step, check)
+	} else {
+		err = s.scanIndex(txn, keyRoot, step, check)
+	}
+
+	if err != nil {
+		return idList{}, err
+	}
+
+	// Closest first
+	scores := map[gouuidv6.UUID]float64{}
+	for id, distance := range distances {
+		scores[id] = -float64(distance)
+	}
+
+	return rankedIDs(scores), nil
+}
+
+// scanIndex checks every value in the field's index.
+// Values are in order, so each distinct value only needs to be checked once.
+func (s fuzzySearch) scanIndex(txn *badger.Txn, keyRoot []byte, step *PlanStep, check func(gouuidv6.UUID, []byte)) error {
+	prefix := newIndexKey(keyRoot, s.indexName, nil).bytes()
+
+	var lastValue []byte
+	lastWithinDistance := false
+
+	return iteratePrefix(txn, prefix, false, func(item *badger.Item) error {
+		step.scanned()
+
+		key := item.Key()
+		value := indexContent(key, prefix)
+
+		if lastValue == nil || string(value) != string(lastValue) {
+			lastValue = append([]byte{}, value...)
+			_, lastWithinDistance = s.distance(string(value))
+		}
+
+		if lastWithinDistance {
+			check(extractID(key), value)
+		}
+
+		return nil
+	})
+}
+
+// trigramCandidates only checks the values sharing enough trigrams with the term
+func (s fuzzySearch) trigramCandidates(txn *badger.Txn, keyRoot []byte, step *PlanStep, check func(gouuidv6.UUID, []byte)) error {
+	shared := map[gouuidv6.UUID]int{}
+	values := map[gouuidv6.UUID][]byte{}
+
+	indexName := derivedIndexName(s.indexName, fuzzyTrigramIndex)
+	for _, trigram := range trigrams(s.term) {
+		prefix := append(newIndexMatchKey(keyRoot, indexName, []byte(trigram)).bytes(), []byte(keySeparator)...)
+
+		err := iteratePrefix(txn, prefix, true, func(item *badger.Item) error {
+			step.scanned()
+
+			id := extractID(item.Key())
+			shared[id]++
+
+			if _, found := values[id]; found {
+				return nil
+			}
+
+			return item.Value(func(val []byte) error {
+				values[id] = append([]byte{}, val...)
+				return nil
+			})
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	minShared := s.minSharedTrigrams()
+	for id, n := range shared {
+		if n >= minShared {
+			check(id, values[id])
+		}
+	}
+
+	return nil
+}
+
+// distance returns the edit distance between the term and a value,
+// and whether it is within the maximum
+func (s fuzzySearch) distance(value string) (int, bool) {
+	distance := levenshtein([]rune(s.term), []rune(value), s.maxEdits)
+	return distance, distance <= s.maxEdits
+}
+
+// levenshtein calculates the edit distance between two strings,
+// giving up and returning max+1 as soon as it is clear the distance is greater than max
+func levenshtein(a, b []rune, max int) int {
+	if abs(len(a)-len(b)) > max {
+		return max + 1
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		rowMin := current[0]
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = minInt(rowMin, current[j])
+		}
+
+		if rowMin > max {
+			return max + 1
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func (s fuzzySearch) describe() PlanStep {
+	step := PlanStep{
+		Stage:    PlanStageSearch,
+		Strategy: PlanStrategyScan,
+		Index:    string(s.indexName),
+		Filter:   s.String(),
+	}
+
+	if s.useTrigrams {
+		step.Strategy = PlanStrategySeek
+		step.Index = string(derivedIndexName(s.indexName, fuzzyTrigramIndex))
+	}
+
+	return step
+}
+
+func (s fuzzySearch) component() queryComponent {
+	return queryComponent{queryStringFuzzy, s}
+}
+
+func (s fuzzySearch) String() string {
+	return fmt.Sprintf("%s%s%s%s%v", s.indexName, whereValueSeparator, s.term, whereValueSeparator, s.maxEdits)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+func minInt(first int, rest ...int) int {
+	for _, i := range rest {
+		if i < first {
+			first = i
+		}
+	}
+
+	return first
+}
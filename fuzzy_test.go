@@ -0,0 +1,39 @@
+package tormenta
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_Levenshtein(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		max      int
+		expected int
+	}{
+		{"", "", 2, 0},
+		{"smith", "smith", 2, 0},
+		{"smith", "smyth", 2, 1},
+		{"smith", "smit", 2, 1},
+		{"smith", "smiths", 2, 1},
+		{"smith", "msith", 2, 2},
+		{"kitten", "sitting", 3, 3},
+		{"kitten", "sitting", 1, 2},
+		{"jones", "smith", 2, 3},
+		{"müller", "muller", 1, 1},
+	}
+
+	for _, testCase := range testCases {
+		result := levenshtein([]rune(testCase.a), []rune(testCase.b), testCase.max)
+		if result != testCase.expected {
+			t.Errorf("Testing distance between '%s' and '%s' (max %v). Expected %v, got %v", testCase.a, testCase.b, testCase.max, testCase.expected, result)
+		}
+	}
+}
+
+func Test_Trigrams(t *testing.T) {
+	expected := []string{"  s", " sm", "smi", "mit", "ith", "th ", "h  "}
+	if result := trigrams("smith"); fmt.Sprint(result) != fmt.Sprint(expected) {
+		t.Errorf("Testing trigrams. Expected %v, got %v", expected, result)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"log"
 	"math/rand"
+	"strings"
 	"time"
 )
 
@@ -25,6 +26,11 @@ func MemberString(valid []string, target string) bool {
 
 var nonContentWords = []string{"on", "at", "the", "in", "a"}
 
+// splitWords breaks a string down into the words stored by a 'split' index
+func splitWords(s string) []string {
+	return removeNonContentWords(strings.Split(s, " "))
+}
+
 func removeNonContentWords(strings []string) (results []string) {
 	for _, s := range strings {
 		if !MemberString(nonContentWords, s) {
@@ -0,0 +1,263 @@
+package tormenta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Record history
+// Entities opt in to keeping their history by tagging their model, e.g.
+//
+//	type Invoice struct {
+//		tormenta.Model `tormenta:"history"`
+//		...
+//	}
+//
+// Every time a record is saved or deleted, the version being replaced
+// is kept under a history key, timestamped with when it was replaced:
+// h:root:id:timestamp -> previous value
+// so that the record can be read as it was at any point in time.
+// The history can be limited to a number of versions (`tormenta:"history=50"`)
+// or to an age (`tormenta:"history=2160h"`), older versions being removed on the next write.
+
+const (
+	historyKeyPrefix = "h"
+
+	ErrHistoryRetention = "History retention %s should be a number of versions or a duration"
+)
+
+type historyRetention struct {
+	enabled  bool
+	versions int
+	age      time.Duration
+}
+
+// entityHistoryRetention reads the history setting from the tag on the entity's model
+func entityHistoryRetention(entity Record) (historyRetention, error) {
+	field, ok := recordValue(entity).Type().FieldByName("Model")
+	if !ok || !isTaggedWith(field, tormentaTagHistory) {
+		return historyRetention{}, nil
+	}
+
+	retention := historyRetention{enabled: true}
+	value := tagValue(field, tormentaTagHistory)
+	if value == "" {
+		return retention, nil
+	}
+
+	if versions, err := strconv.Atoi(value); err == nil && versions > 0 {
+		retention.versions = versions
+		return retention, nil
+	}
+
+	if age, err := time.ParseDuration(value); err == nil && age > 0 {
+		retention.age = age
+		return retention, nil
+	}
+
+	return historyRetention{}, fmt.Errorf(ErrHistoryRetention, value)
+}
+
+func historyPrefix(root []byte, id gouuidv6.UUID) []byte {
+	return bytes.Join([][]byte{[]byte(historyKeyPrefix), root, id.Bytes(), {}}, []byte(keySeparator))
+}
+
+func historyKey(root []byte, id gouuidv6.UUID, t time.Time) []byte {
+	return append(historyPrefix(root, id), historyTimestamp(t)...)
+}
+
+func historyTimestamp(t time.Time) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(t.UnixNano()))
+	return b
+}
+
+func historyKeyTime(key, prefix []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(key[len(prefix):])))
+}
+
+// saveHistory keeps the current version of a record, for entities with history,
+// before it is overwritten or deleted in the same transaction
+func (db DB) saveHistory(txn *badger.Txn, entity Record, root []byte, id gouuidv6.UUID) error {
+	retention, err := entityHistoryRetention(entity)
+	if err != nil || !retention.enabled {
+		return err
+	}
+
+	item, err := txn.Get(newContentKey(root, id).bytes())
+	if err == badger.ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := txn.Set(historyKey(root, id, now), value); err != nil {
+		return err
+	}
+
+	return pruneHistory(txn, historyPrefix(root, id), retention, now)
+}
+
+// pruneHistory removes the versions of a record beyond the retention limits
+func pruneHistory(txn *badger.Txn, prefix []byte, retention historyRetention, now time.Time) error {
+	if retention.versions == 0 && retention.age == 0 {
+		return nil
+	}
+
+	var keys [][]byte
+	err := iteratePrefix(txn, prefix, false, func(item *badger.Item) error {
+		keys = append(keys, item.KeyCopy(nil))
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		tooMany := retention.versions > 0 && len(keys)-i > retention.versions
+		tooOld := retention.age > 0 && historyKeyTime(key, prefix).Before(now.Add(-retention.age))
+		if !tooMany && !tooOld {
+			break
+		}
+
+		if err := txn.Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// History retrieves every version of a record, oldest first, into the target slice,
+// ending with the current version unless the record has been deleted.
+// The LastUpdated time of each version is when it was saved.
+func (db DB) History(target interface{}, id gouuidv6.UUID) (int, error) {
+	t := time.Now()
+
+	txn := db.KV.NewTransaction(false)
+	defer txn.Discard()
+
+	n, err := db.history(txn, target, id)
+
+	db.observe(OpGet, KeyRoot(target), t, n, err)
+	db.debugLogGet(target, t, n, err, id)
+	return n, err
+}
+
+func (db DB) history(txn *badger.Txn, target interface{}, id gouuidv6.UUID) (int, error) {
+	root := KeyRoot(target)
+	prefix := historyPrefix(root, id)
+	records := newResultsArray(target)
+
+	err := iteratePrefix(txn, prefix, true, func(item *badger.Item) error {
+		record := newRecordFromSlice(target)
+		if err := item.Value(func(val []byte) error {
+			return db.unserialise(val, record)
+		}); err != nil {
+			return err
+		}
+
+		record.SetID(id)
+		record.GetCreated()
+		record.PostGet(noCTX)
+		records = reflect.Append(records, recordValue(record))
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	current := newRecordFromSlice(target)
+	found, err := db.get(txn, current, noCTX, id)
+	if err != nil {
+		return 0, err
+	} else if found {
+		records = reflect.Append(records, recordValue(current))
+	}
+
+	setResultsArrayOntoTarget(target, records)
+	return records.Len(), nil
+}
+
+// GetAsOf retrieves a record as it was at the given time.
+// If the record didn't exist at that time, or its history from then
+// has since been removed according to the retention, it returns false.
+func (db DB) GetAsOf(entity Record, id gouuidv6.UUID, t time.Time) (bool, error) {
+	start := time.Now()
+
+	txn := db.KV.NewTransaction(false)
+	defer txn.Discard()
+
+	found, err := db.getAsOf(txn, entity, id, t)
+
+	var n int
+	if found {
+		n = 1
+	}
+
+	db.observe(OpGet, KeyRoot(entity), start, n, err)
+	db.debugLogGet(entity, start, n, err, id)
+	return found, err
+}
+
+func (db DB) getAsOf(txn *badger.Txn, entity Record, id gouuidv6.UUID, t time.Time) (bool, error) {
+	if t.Before(id.Time()) {
+		return false, nil
+	}
+
+	// The version at time t is the first one replaced after t
+	root := KeyRoot(entity)
+	prefix := historyPrefix(root, id)
+	seekFrom := append(append([]byte{}, prefix...), historyTimestamp(t.Add(time.Nanosecond))...)
+
+	it := txn.NewIterator(badger.DefaultIteratorOptions)
+	defer it.Close()
+
+	version := newRecord(entity)
+
+	it.Seek(seekFrom)
+	if !it.ValidForPrefix(prefix) {
+		// Not replaced since, so it's the current version, if it still exists
+		if found, err := db.get(txn, version, noCTX, id); err != nil || !found {
+			return false, err
+		}
+	} else {
+		if err := it.Item().Value(func(val []byte) error {
+			return db.unserialise(val, version)
+		}); err != nil {
+			return false, err
+		}
+
+		version.SetID(id)
+		version.GetCreated()
+		version.PostGet(noCTX)
+	}
+
+	// If the versions before it have been pruned, the first version replaced after t
+	// may not have been saved until after t, in which case we don't know the version at t
+	if versionSaved(version).After(t) {
+		return false, nil
+	}
+
+	recordValue(entity).Set(recordValue(version))
+	return true, nil
+}
+
+func versionSaved(record Record) time.Time {
+	return recordValue(record).FieldByName("Model").Interface().(Model).LastUpdated
+}
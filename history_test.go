@@ -0,0 +1,166 @@
+package tormenta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_History(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	invoice := testtypes.Invoice{Number: 1, Amount: 100}
+	db.Save(&invoice)
+	time.Sleep(10 * time.Millisecond)
+	afterFirstSave := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	invoice.Amount = 200
+	db.Save(&invoice)
+	time.Sleep(10 * time.Millisecond)
+	afterSecondSave := time.Now()
+	time.Sleep(10 * time.Millisecond)
+
+	invoice.Amount = 300
+	db.Save(&invoice)
+
+	// History
+	var versions []testtypes.Invoice
+	n, err := db.History(&versions, invoice.ID)
+	if err != nil {
+		t.Fatalf("Testing history. Didn't expect error [%v]", err)
+	}
+
+	if n != 3 || len(versions) != 3 {
+		t.Fatalf("Testing history. Expected 3 versions, got %v", n)
+	}
+
+	for i, expected := range []float64{100, 200, 300} {
+		if versions[i].Amount != expected || versions[i].ID != invoice.ID || versions[i].Number != 1 {
+			t.Errorf("Testing history. Expected version %v to have amount %v, got %v", i, expected, versions[i])
+		}
+
+		if i > 0 && !versions[i].LastUpdated.After(versions[i-1].LastUpdated) {
+			t.Errorf("Testing history. Expected versions to be in order of saving")
+		}
+	}
+
+	// Point in time reads
+	testCases := []struct {
+		testName string
+		at       time.Time
+		found    bool
+		amount   float64
+	}{
+		{"before creation", invoice.ID.Time().Add(-time.Second), false, 0},
+		{"after first save", afterFirstSave, true, 100},
+		{"after second save", afterSecondSave, true, 200},
+		{"now", time.Now(), true, 300},
+	}
+
+	for _, testCase := range testCases {
+		var result testtypes.Invoice
+		found, err := db.GetAsOf(&result, invoice.ID, testCase.at)
+		if err != nil {
+			t.Errorf("Testing get as of %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if found != testCase.found {
+			t.Errorf("Testing get as of %s. Expected found to be %v, got %v", testCase.testName, testCase.found, found)
+		}
+
+		if result.Amount != testCase.amount {
+			t.Errorf("Testing get as of %s. Expected amount %v, got %v", testCase.testName, testCase.amount, result.Amount)
+		}
+	}
+
+	// Deletion keeps the history
+	time.Sleep(10 * time.Millisecond)
+	beforeDeletion := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	db.Delete(&testtypes.Invoice{}, invoice.ID)
+
+	versions = []testtypes.Invoice{}
+	if n, _ := db.History(&versions, invoice.ID); n != 3 || versions[2].Amount != 300 {
+		t.Errorf("Testing history after deletion. Expected 3 versions, the last with amount 300, got %v", versions)
+	}
+
+	var result testtypes.Invoice
+	if found, _ := db.GetAsOf(&result, invoice.ID, beforeDeletion); !found || result.Amount != 300 {
+		t.Errorf("Testing get as of before deletion. Expected amount 300, got %v (found %v)", result.Amount, found)
+	}
+
+	if found, _ := db.GetAsOf(&testtypes.Invoice{}, invoice.ID, time.Now()); found {
+		t.Error("Testing get as of after deletion. Expected not to find the record")
+	}
+}
+
+func Test_History_Retention(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	quote := testtypes.Quote{Amount: 1}
+	db.Save(&quote)
+
+	times := map[int]time.Time{}
+	for i := 2; i <= 5; i++ {
+		time.Sleep(time.Millisecond)
+		times[i-1] = time.Now()
+		time.Sleep(time.Millisecond)
+
+		quote.Amount = float64(i)
+		db.Save(&quote)
+	}
+
+	// The current version and the last 2 previous ones
+	var versions []testtypes.Quote
+	n, _ := db.History(&versions, quote.ID)
+	if n != 3 {
+		t.Fatalf("Testing history retention. Expected 3 versions, got %v", n)
+	}
+
+	for i, expected := range []float64{3, 4, 5} {
+		if versions[i].Amount != expected {
+			t.Errorf("Testing history retention. Expected version %v to have amount %v, got %v", i, expected, versions[i].Amount)
+		}
+	}
+
+	// Point in time reads from before the retained versions aren't possible
+	for amount := 1; amount <= 4; amount++ {
+		var result testtypes.Quote
+		found, err := db.GetAsOf(&result, quote.ID, times[amount])
+		if err != nil {
+			t.Errorf("Testing get as of with retention, amount %v. Didn't expect error [%v]", amount, err)
+		}
+
+		expectFound := amount >= 3
+		if found != expectFound {
+			t.Errorf("Testing get as of with retention, amount %v. Expected found to be %v, got %v", amount, expectFound, found)
+		}
+
+		if expectFound && result.Amount != float64(amount) {
+			t.Errorf("Testing get as of with retention. Expected amount %v, got %v", amount, result.Amount)
+		} else if !expectFound && result.Amount != 0 {
+			t.Errorf("Testing get as of with retention, amount %v. Expected the record not to be set, got amount %v", amount, result.Amount)
+		}
+	}
+}
+
+func Test_History_NotEnabled(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	fullStruct := testtypes.FullStruct{IntField: 1}
+	db.Save(&fullStruct)
+
+	fullStruct.IntField = 2
+	db.Save(&fullStruct)
+
+	var versions []testtypes.FullStruct
+	if n, _ := db.History(&versions, fullStruct.ID); n != 1 || versions[0].IntField != 2 {
+		t.Errorf("Testing history for an entity without history. Expected just the current version, got %v", n)
+	}
+}
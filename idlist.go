@@ -26,6 +26,50 @@ func (ids idList) sort(reverse bool) {
 	sort.Slice(ids, compareFunc)
 }
 
+// unique removes duplicate IDs, keeping the first occurrence of each
+func (ids idList) unique() (result idList) {
+	seen := map[gouuidv6.UUID]bool{}
+
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	return
+}
+
+// limitOffset skips the first N ids, and restricts the list to the limit (if any)
+func (ids idList) limitOffset(limit, offset int) idList {
+	if offset >= len(ids) {
+		return idList{}
+	}
+
+	ids = ids[offset:]
+	if limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
+	}
+
+	return ids
+}
+
+// keep returns the ids which are also in the other list, preserving their order
+func (ids idList) keep(other idList) (result idList) {
+	found := map[gouuidv6.UUID]bool{}
+	for _, id := range other {
+		found[id] = true
+	}
+
+	for _, id := range ids {
+		if found[id] {
+			result = append(result, id)
+		}
+	}
+
+	return
+}
+
 // for OR
 func union(listsOfIDs ...idList) (result idList) {
 	masterMap := map[gouuidv6.UUID]bool{}
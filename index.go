@@ -17,17 +17,44 @@ import (
 // i:indexname:root:indexcontent:entityID
 // i:fullStruct:customer:5:324ds-3werwf-234wef-23wef
 
-func index(txn *badger.Txn, entity Record) error {
-	keys := indexStruct(
-		recordValue(entity),
-		entity,
-		KeyRoot(entity),
-		entity.GetID(),
-		nil,
-	)
+// indexEntry is a single key to be written to the index.
+// Most index types carry all their information in the key and have an empty value,
+// but some (e.g. full text) store extra information in the value
+type indexEntry struct {
+	key, value []byte
+}
+
+// indexEntries builds all the index entries for a record,
+// from its fields and any computed indexes
+func (db DB) indexEntries(entity Record) ([]indexEntry, error) {
+	keyRoot, id, opts := KeyRoot(entity), entity.GetID(), db.indexOptions()
+
+	entries, err := indexStruct(recordValue(entity), entity, keyRoot, id, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	computedEntries, err := getComputedIndexes(entity, keyRoot, id, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(entries, computedEntries...), nil
+}
 
-	for i := range keys {
-		if err := txn.Set(keys[i], []byte{}); err != nil {
+func (db DB) index(txn *badger.Txn, entity Record) error {
+	entries, err := db.indexEntries(entity)
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		value := entries[i].value
+		if value == nil {
+			value = []byte{}
+		}
+
+		if err := txn.Set(entries[i].key, value); err != nil {
 			return err
 		}
 	}
@@ -35,17 +62,14 @@ func index(txn *badger.Txn, entity Record) error {
 	return nil
 }
 
-func deIndex(txn *badger.Txn, entity Record) error {
-	keys := indexStruct(
-		recordValue(entity),
-		entity,
-		KeyRoot(entity),
-		entity.GetID(),
-		nil,
-	)
+func (db DB) deIndex(txn *badger.Txn, entity Record) error {
+	entries, err := db.indexEntries(entity)
+	if err != nil {
+		return err
+	}
 
-	for i := range keys {
-		if err := txn.Delete(keys[i]); err != nil {
+	for i := range entries {
+		if err := txn.Delete(entries[i].key); err != nil {
 			return err
 		}
 	}
@@ -53,7 +77,7 @@ func deIndex(txn *badger.Txn, entity Record) error {
 	return nil
 }
 
-func indexStruct(v reflect.Value, entity Record, keyRoot []byte, id gouuidv6.UUID, path []byte) (keys [][]byte) {
+func indexStruct(v reflect.Value, entity Record, keyRoot []byte, id gouuidv6.UUID, path []byte, opts indexOptions) (keys []indexEntry, err error) {
 	for i := 0; i < v.NumField(); i++ {
 
 		fieldType := v.Type().Field(i)
@@ -64,52 +88,139 @@ func indexStruct(v reflect.Value, entity Record, keyRoot []byte, id gouuidv6.UUI
 
 		if !isTaggedWith(fieldType, tormentaTagNoIndex, tormentaTagNoSave) {
 
-			switch fieldType.Type.Kind() {
+			// Zero values are not indexed for sparse fields,
+			// and the record decides on its conditional indexes
+			if isSparse(fieldType) && v.Field(i).IsZero() {
+				continue
+			}
+
+			if !indexIf(entity, indexName) {
+				continue
+			}
 
-			// Slice: index members individually
+			// Big numbers and types implementing IndexValuer
+			// have their own order preserving encodings
+			if isCustomIndexType(fieldType.Type) {
+				content, err := customIndexContent(v.Field(i))
+				if err != nil {
+					return nil, err
+				}
+
+				if content != nil {
+					keys = append(keys, makeIndexEntry(keyRoot, id, indexName, content))
+				}
+
+				continue
+			}
+
+			// Pointers are indexed as the values they point to,
+			// and nil pointers are not indexed at all
+			field := v.Field(i)
+			if field.Kind() == reflect.Ptr {
+				if field.IsNil() {
+					continue
+				}
+
+				field = field.Elem()
+			}
+
+			switch field.Kind() {
+
+			// Map: index each entry under its key
+			case reflect.Map:
+				mapKeys, err := getMapIndexKeys(field, keyRoot, id, indexName, fieldStringEncoding(fieldType), opts)
+				if err != nil {
+					return nil, err
+				}
+
+				keys = append(keys, mapKeys...)
+
+			// Slice: index members individually,
+			// or if tagged 'nested', the fields of each member
 			case reflect.Slice:
-				keys = append(keys, getMultipleIndexKeys(v.Field(i), keyRoot, id, indexName)...)
+				if isTaggedWith(fieldType, tormentaTagNestedIndex) {
+					nestedKeys, err := getNestedSliceIndexKeys(field, entity, keyRoot, id, indexName, opts)
+					if err != nil {
+						return nil, err
+					}
+
+					keys = append(keys, nestedKeys...)
+				} else {
+					keys = append(keys, getMultipleIndexKeys(field, keyRoot, id, indexName, fieldStringEncoding(fieldType), opts)...)
+				}
 
 			// Array: index members individually
 			case reflect.Array:
 				// UUIDV6s are arrays, so we intercept them here
-				if fieldType.Type == reflect.TypeOf(gouuidv6.UUID{}) {
-					keys = append(keys, makeIndexKey(keyRoot, id, indexName, v.Field(i).Interface()))
+				if field.Type() == reflect.TypeOf(gouuidv6.UUID{}) {
+					keys = append(keys, makeIndexEntry(keyRoot, id, indexName, field.Interface()))
 				} else {
-					keys = append(keys, getMultipleIndexKeys(v.Field(i), keyRoot, id, indexName)...)
+					keys = append(keys, getMultipleIndexKeys(field, keyRoot, id, indexName, fieldStringEncoding(fieldType), opts)...)
 				}
 
-			// Strings: either straight index, or split by words
+			// Strings: either straight index, split by words, or full text,
+			// optionally with trigrams, reversed and/or collated
 			case reflect.String:
-				if isTaggedWith(fieldType, tormentaTagSplit) {
-					keys = append(keys, getSplitStringIndexes(v.Field(i), keyRoot, id, indexName)...)
+				encoding := fieldStringEncoding(fieldType)
+
+				if isTaggedWith(fieldType, tormentaTagFullText) {
+					keys = append(keys, getFullTextIndexes(field, keyRoot, id, indexName, fullTextAnalyzer(fieldType))...)
+				} else if isTaggedWith(fieldType, tormentaTagSplit) {
+					keys = append(keys, getSplitStringIndexes(field, keyRoot, id, indexName, encoding)...)
 				} else {
-					keys = append(keys, makeIndexKey(keyRoot, id, indexName, v.Field(i).Interface()))
+					keys = append(keys, makeIndexEntry(keyRoot, id, indexName, encoding.encode(field.String())))
+				}
+
+				// Trigrams for fuzzy and substring searching,
+				// and reversed strings for suffix searching
+				// are in addition to the regular index
+				if isTaggedWith(fieldType, tormentaTagTrigram) {
+					keys = append(keys, getTrigramIndexes(field, keyRoot, id, indexName, encoding)...)
+				}
+
+				if isTaggedWith(fieldType, tormentaTagReversed) {
+					keys = append(keys, getReversedIndex(field, keyRoot, id, indexName, encoding))
+				}
+
+				// Collation sort keys for locale aware ordering
+				if collation := fieldCollation(fieldType); collation != "" {
+					encoding.collation = collation
+					keys = append(keys, getCollationIndex(field, keyRoot, id, indexName, encoding))
 				}
 
 			// Anonymous/ Nested Structs
 			case reflect.Struct:
 				// time.Time is a struct, so we'll intercept it here
 				// and send it to the index key maker which will translate it to int64
-				// see below interfaceToBytes for more on that
-				f := v.Field(i).Interface()
-				if _, ok := f.(time.Time); ok {
-					keys = append(keys, makeIndexKey(keyRoot, id, indexName, f))
+				// see below interfaceToBytes for more on that.
+				// Defined time types are indexed in the same way.
+				if t, ok := asTime(field.Interface()); ok {
+					keys = append(keys, makeIndexEntry(keyRoot, id, indexName, opts.timeContent(t)))
 				}
 
 				// Recursively index embedded structs
 				if fieldType.Anonymous {
-					keys = append(keys, indexStruct(v.Field(i), entity, keyRoot, id, nil)...)
+					embeddedKeys, err := indexStruct(field, entity, keyRoot, id, nil, opts)
+					if err != nil {
+						return nil, err
+					}
+
+					keys = append(keys, embeddedKeys...)
 				}
 
 				// And named structs, if they are tagged 'nested'
 				// But construct the index with path separators
 				if isTaggedWith(fieldType, tormentaTagNestedIndex) {
-					keys = append(keys, indexStruct(v.Field(i), entity, keyRoot, id, indexName)...)
+					nestedKeys, err := indexStruct(field, entity, keyRoot, id, indexName, opts)
+					if err != nil {
+						return nil, err
+					}
+
+					keys = append(keys, nestedKeys...)
 				}
 
 			default:
-				keys = append(keys, makeIndexKey(keyRoot, id, indexName, v.Field(i).Interface()))
+				keys = append(keys, makeIndexEntry(keyRoot, id, indexName, opts.content(field)))
 			}
 		}
 	}
@@ -117,6 +228,8 @@ func indexStruct(v reflect.Value, entity Record, keyRoot []byte, id gouuidv6.UUI
 	return
 }
 
+var typeTime = reflect.TypeOf(time.Time{})
+
 // MakeIndexKey constructs an index key
 func MakeIndexKey(root []byte, id gouuidv6.UUID, indexName []byte, indexContent interface{}) []byte {
 	return makeIndexKey(root, id, indexName, indexContent)
@@ -135,23 +248,94 @@ func makeIndexKey(root []byte, id gouuidv6.UUID, indexName []byte, indexContent
 	)
 }
 
-func getMultipleIndexKeys(v reflect.Value, root []byte, id gouuidv6.UUID, indexName []byte) (keys [][]byte) {
+// makeIndexEntry constructs an index entry with no value
+func makeIndexEntry(root []byte, id gouuidv6.UUID, indexName []byte, indexContent interface{}) indexEntry {
+	return indexEntry{key: makeIndexKey(root, id, indexName, indexContent)}
+}
+
+func getMultipleIndexKeys(v reflect.Value, root []byte, id gouuidv6.UUID, indexName []byte, encoding stringEncoding, opts indexOptions) (keys []indexEntry) {
 	for i := 0; i < v.Len(); i++ {
-		key := makeIndexKey(root, id, indexName, v.Index(i).Interface())
+		member := v.Index(i)
+		if member.Kind() == reflect.Ptr {
+			if member.IsNil() {
+				continue
+			}
+
+			member = member.Elem()
+		}
+
+		indexContent := opts.content(member)
+		if member.Kind() == reflect.String {
+			indexContent = encoding.encode(member.String())
+		}
+
+		key := makeIndexEntry(root, id, indexName, indexContent)
 		keys = append(keys, key)
 	}
 
 	return
 }
 
-func getSplitStringIndexes(v reflect.Value, root []byte, id gouuidv6.UUID, indexName []byte) (keys [][]byte) {
-	strings := strings.Split(v.String(), " ")
+// valueIndexContent returns the index content for a single value,
+// e.g. a map entry or a computed index, encoded according to its kind.
+// Nil values return nil and are not indexed.
+func valueIndexContent(v reflect.Value, encoding stringEncoding, opts indexOptions) (interface{}, error) {
+	// Interfaces, e.g. the values of a map[string]interface{}, hold the actual value
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		v = interfaceIndexValue(v.Elem())
+	}
 
-	// Clean non-content words
-	strings = removeNonContentWords(strings)
+	if isCustomIndexType(v.Type()) {
+		content, err := customIndexContent(v)
+		if content == nil || err != nil {
+			return nil, err
+		}
+
+		return content, nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.String {
+		return encoding.encode(v.String()), nil
+	}
+
+	return opts.content(v), nil
+}
 
-	for _, s := range strings {
-		key := makeIndexKey(root, id, indexName, s)
+// getNestedSliceIndexKeys indexes the fields of each struct in the slice,
+// as for a nested struct, i.e. under Field.SubField
+func getNestedSliceIndexKeys(v reflect.Value, entity Record, root []byte, id gouuidv6.UUID, indexName []byte, opts indexOptions) (keys []indexEntry, err error) {
+	for i := 0; i < v.Len(); i++ {
+		member := reflect.Indirect(v.Index(i))
+		if member.Kind() != reflect.Struct {
+			continue
+		}
+
+		memberKeys, err := indexStruct(member, entity, root, id, indexName, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, memberKeys...)
+	}
+
+	return
+}
+
+func getSplitStringIndexes(v reflect.Value, root []byte, id gouuidv6.UUID, indexName []byte, encoding stringEncoding) (keys []indexEntry) {
+	for _, s := range splitWords(v.String()) {
+		key := makeIndexEntry(root, id, indexName, encoding.encode(s))
 		keys = append(keys, key)
 	}
 
@@ -164,6 +348,12 @@ func interfaceToBytes(value interface{}) []byte {
 		return []byte{}
 	}
 
+	// Strings that have already been encoded according to the field's
+	// string encoding options are passed through as they are
+	if b, ok := value.([]byte); ok {
+		return b
+	}
+
 	buf := new(bytes.Buffer)
 
 	switch reflect.ValueOf(value).Type().Kind() {
@@ -195,7 +385,7 @@ func interfaceToBytes(value interface{}) []byte {
 
 	case reflect.Struct:
 		// time.Time is a struct, so we encode/decode as int64 (unix seconds)
-		if t, ok := reflect.ValueOf(value).Interface().(time.Time); ok {
+		if t, ok := asTime(value); ok {
 			binary.Write(buf, binary.BigEndian, t.Unix())
 			return flipInt(buf.Bytes())
 		}
@@ -206,11 +396,17 @@ func interfaceToBytes(value interface{}) []byte {
 }
 
 // interfaceToBytes encodes values to bytes where the input interface could potentially be anything.  This is used when consulting indices, rather than building them, and thus the input value is provided by the user.  The technique we use is to work out the target type from the original struct field and provide it as an argument to this function.  We then do our best to cast the provided value into the target type.  The best way I've found to do this is for the number types is to output a string and then parse it as a 64bit int, uint or float and then cast to the target numerical type as required.  For bools, if the provided interface is not a bool, we try decoding it as a string (e.g. "false", "f") and then just defualt to False.
-func interfaceToBytesWithOverride(value interface{}, typeOverride reflect.Kind) ([]byte, error) {
+func interfaceToBytesWithOverride(value interface{}, typeOverride reflect.Kind, encoding stringEncoding) ([]byte, error) {
 	if value == nil {
 		return []byte{}, nil
 	}
 
+	// Big numbers and IndexValuers encode themselves,
+	// whatever the kind of the field
+	if isCustomValue(value) {
+		return encodeCustomValue(value)
+	}
+
 	buf := new(bytes.Buffer)
 
 	switch typeOverride {
@@ -284,14 +480,29 @@ func interfaceToBytesWithOverride(value interface{}, typeOverride reflect.Kind)
 
 	case reflect.Struct:
 		// time.Time is a struct, so we encode/decode as int64 (unix seconds)
-		if t, ok := reflect.ValueOf(value).Interface().(time.Time); ok {
+		if t, ok := asTime(value); ok {
 			binary.Write(buf, binary.BigEndian, t.Unix())
 			return flipInt(buf.Bytes()), nil
 		}
 	}
 
-	// Everything else as a string (lower case)
-	return []byte(strings.ToLower(fmt.Sprintf("%v", value))), nil
+	// Everything else as a string, encoded according to the field's options
+	// (lower case by default)
+	return encoding.encode(fmt.Sprintf("%v", value)), nil
+}
+
+// asTime returns the value as a time.Time if it is one,
+// or if it is of a defined type based on time.Time
+func asTime(value interface{}) (time.Time, bool) {
+	if t, ok := value.(time.Time); ok {
+		return t, true
+	}
+
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Struct && v.Type().ConvertibleTo(typeTime) {
+		return v.Convert(typeTime).Interface().(time.Time), true
+	}
+
+	return time.Time{}, false
 }
 
 // BIT ORDERING HELPERS
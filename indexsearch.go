@@ -25,6 +25,9 @@ type indexSearch struct {
 
 	indexKind reflect.Kind
 
+	// Are numbers indexed in the uniform format?
+	uniformNumeric bool
+
 	// Offet - start returning results N entities from the beginning
 	// offsetCounter used to track the offset
 	offset, offsetCounter int
@@ -34,6 +37,9 @@ type indexSearch struct {
 
 	sumIndexName []byte
 	sumTarget    interface{}
+
+	// Profiling for this search, if required
+	step *PlanStep
 }
 
 func (i indexSearch) isLimitMet(noIDsSoFar int) bool {
@@ -63,6 +69,7 @@ func (i indexSearch) execute(txn *badger.Txn) (ids idList) {
 	// Set ranges and init the offset counter
 	i.setRanges()
 	i.offsetCounter = i.offset
+	i.step.seek(i.seekFrom, i.validTo)
 
 	// Create a map of the ids we are looking for
 	sourceIDs := map[gouuidv6.UUID]bool{}
@@ -74,6 +81,8 @@ func (i indexSearch) execute(txn *badger.Txn) (ids idList) {
 	defer it.Close()
 
 	for it.Seek(i.seekFrom); it.ValidForPrefix(i.validTo) && !i.isLimitMet(len(ids)); it.Next() {
+		i.step.scanned()
+
 		item := it.Item()
 		thisID := extractID(item.Key())
 
@@ -91,7 +100,16 @@ func (i indexSearch) execute(txn *badger.Txn) (ids idList) {
 
 		// If required, take advantage to tally the sum
 		if len(i.sumIndexName) > 0 && i.sumTarget != nil {
-			quickSum(i.sumTarget, item)
+			_, isDecoder := i.sumTarget.(IndexValueDecoder)
+
+			switch {
+			case isBigNumberTarget(i.sumTarget):
+				quickSumBig(i.sumTarget, indexContent(item.Key(), i.validTo))
+			case i.uniformNumeric && !isDecoder:
+				quickSumUniform(i.sumTarget, item)
+			default:
+				quickSum(i.sumTarget, item)
+			}
 		}
 
 		ids = append(ids, thisID)
@@ -99,3 +117,21 @@ func (i indexSearch) execute(txn *badger.Txn) (ids idList) {
 
 	return
 }
+
+// iteratePrefix calls fn for every key with the given prefix,
+// stopping at the first error
+func iteratePrefix(txn *badger.Txn, prefix []byte, prefetchValues bool, fn func(item *badger.Item) error) error {
+	options := badger.DefaultIteratorOptions
+	options.PrefetchValues = prefetchValues
+
+	it := txn.NewIterator(options)
+	defer it.Close()
+
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		if err := fn(it.Item()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
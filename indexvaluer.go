@@ -0,0 +1,172 @@
+package tormenta
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Custom index values.
+// By default, fields are indexed according to their reflect.Kind,
+// so a defined type such as `type Money int64` is indexed as an int64,
+// and struct types (other than time.Time) aren't indexed at all.
+// Types can take control of their index encoding by implementing IndexValuer,
+// and of decoding (for sums) by implementing IndexValueDecoder.
+// Big numbers (see bignumber.go) are handled in the same way.
+
+const (
+	ErrIndexValueInput = "%v (%T) could not be interpreted as a %s"
+)
+
+// IndexValuer is implemented by types that encode their own index content,
+// e.g. money, decimal or geohash types.  For range searches and ordering to work,
+// the byte order of the encoded values must be the same as the order of the values themselves.
+// Query input for fields of these types should be of the same type,
+// or of a type that can be converted to it.
+type IndexValuer interface {
+	IndexValue() ([]byte, error)
+}
+
+// IndexValueDecoder is the counterpart of IndexValuer,
+// decoding index content back into the value.
+// Sum targets implementing IndexValueDecoder are summed according to their underlying numeric kind.
+type IndexValueDecoder interface {
+	DecodeIndexValue([]byte) error
+}
+
+var (
+	typeIndexValuer       = reflect.TypeOf((*IndexValuer)(nil)).Elem()
+	typeIndexValueDecoder = reflect.TypeOf((*IndexValueDecoder)(nil)).Elem()
+)
+
+// isCustomIndexType reports whether values of this type
+// (or pointers to it) bypass the default index encoding
+func isCustomIndexType(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return isBigNumberType(t) || t.Implements(typeIndexValuer) || reflect.PtrTo(t).Implements(typeIndexValuer)
+}
+
+func isCustomValue(value interface{}) bool {
+	return value != nil && isCustomIndexType(reflect.TypeOf(value))
+}
+
+// customIndexContent encodes a field value of a custom index type.
+// Nil pointers return no content and are not indexed.
+func customIndexContent(v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+	} else if v.CanAddr() {
+		// The math/big methods, for example, are on the pointer
+		v = v.Addr()
+	} else {
+		v = pointerTo(v)
+	}
+
+	return encodeCustomValue(v.Interface())
+}
+
+// encodeCustomValue encodes values which are, or point to, big numbers or IndexValuers
+func encodeCustomValue(value interface{}) ([]byte, error) {
+	if b, ok := encodeBigNumber(value); ok {
+		return b, nil
+	}
+
+	if valuer, ok := value.(IndexValuer); ok {
+		return valuer.IndexValue()
+	}
+
+	// IndexValuers with pointer receivers, passed by value
+	if v := reflect.ValueOf(value); v.IsValid() && v.Kind() != reflect.Ptr && reflect.PtrTo(v.Type()).Implements(typeIndexValuer) {
+		return encodeCustomValue(pointerTo(v).Interface())
+	}
+
+	return nil, fmt.Errorf(ErrIndexValueInput, value, value, "custom index value")
+}
+
+// customIndexInput encodes a user provided value for a search on a field of a custom index type
+func customIndexInput(value interface{}, t reflect.Type) ([]byte, error) {
+	if value == nil {
+		return []byte{}, nil
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if isBigNumberType(t) {
+		return bigNumberInput(value, t)
+	}
+
+	// Input of a different type, e.g. an int for a `type Money int64` field,
+	// is converted to the field type before encoding
+	if v := reflect.ValueOf(value); v.Type() != t && v.Type() != reflect.PtrTo(t) {
+		converted, ok := convertInput(v, t)
+		if !ok {
+			return nil, fmt.Errorf(ErrIndexValueInput, value, value, t)
+		}
+
+		value = converted.Interface()
+	}
+
+	return encodeCustomValue(value)
+}
+
+// convertInput converts between types of the same kind, or between numeric kinds
+func convertInput(v reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	sameKind := v.Kind() == t.Kind() || (isNumericKind(v.Kind()) && isNumericKind(t.Kind()))
+	if !sameKind || !v.Type().ConvertibleTo(t) {
+		return v, false
+	}
+
+	return v.Convert(t), true
+}
+
+// comparableInput makes sure that query input can be compared with ==,
+// which is not the case for big numbers passed by value
+func comparableInput(value interface{}) interface{} {
+	if value == nil || reflect.TypeOf(value).Comparable() {
+		return value
+	}
+
+	return pointerTo(reflect.ValueOf(value)).Interface()
+}
+
+// pointerTo returns a pointer to a copy of the value
+func pointerTo(v reflect.Value) reflect.Value {
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p
+}
+
+// Decoding
+
+// quickSumDecoded decodes an index value into a new value of the sum target's type
+// and adds it to the target
+func quickSumDecoded(target IndexValueDecoder, key []byte) {
+	t := reflect.Indirect(reflect.ValueOf(target))
+
+	decoded := reflect.New(t.Type())
+	extractIndexValue(key, decoded.Interface())
+
+	d := decoded.Elem()
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		t.SetInt(t.Int() + d.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		t.SetUint(t.Uint() + d.Uint())
+	case reflect.Float32, reflect.Float64:
+		t.SetFloat(t.Float() + d.Float())
+	}
+}
@@ -11,8 +11,13 @@ import (
 const (
 	contentKeyPrefix  = "c"
 	indexKeyPrefix    = "i"
+	previousKeyPrefix = "p"
 	indexKeySeparator = "."
 	keySeparator      = "~±^"
+
+	// Separates an index name from the type of a supplementary index
+	// built alongside it, e.g. MyField#length
+	derivedIndexSeparator = "#"
 )
 
 type key struct {
@@ -45,6 +50,11 @@ func nestedIndexKeyRoot(base, next []byte) []byte {
 	return bytes.Join([][]byte{base, next}, []byte(indexKeySeparator))
 }
 
+// derivedIndexName is the name of a supplementary index built alongside the main index for a field
+func derivedIndexName(indexName []byte, kind string) []byte {
+	return bytes.Join([][]byte{indexName, []byte(kind)}, []byte(derivedIndexSeparator))
+}
+
 func newIndexMatchKey(root, indexName, indexContent []byte, id ...gouuidv6.UUID) key {
 	return withID(key{
 		isIndex:      true,
@@ -118,20 +128,45 @@ func extractID(b []byte) (uuid gouuidv6.UUID) {
 
 func extractIndexValue(b []byte, i interface{}) {
 	s := bytes.Split(b, []byte(keySeparator))
-	indexValueBytes := s[3]
+	decodeIndexValue(bytes.Join(s[3:len(s)-1], []byte(keySeparator)), i)
+}
+
+// decodeIndexValue decodes index content into i,
+// which should be a pointer to a value of the indexed type
+func decodeIndexValue(content []byte, i interface{}) {
+	// Custom types decode their own index values,
+	// which may be binary and so contain the separator
+	if decoder, ok := i.(IndexValueDecoder); ok {
+		decoder.DecodeIndexValue(content)
+		return
+	}
 
-	// For unsigned ints, we need to flip the sign bit back
+	// For signed ints and floats, we need to flip the bits back,
+	// on a copy so as not to modify the key
+	content = append([]byte{}, content...)
 	switch i.(type) {
 	case *int, *int8, *int16, *int32, *int64:
-		flipInt(indexValueBytes)
+		flipInt(content)
 	case *float64, *float32:
-		revertFloat(indexValueBytes)
+		revertFloat(content)
 	}
 
-	buf := bytes.NewBuffer(indexValueBytes)
+	buf := bytes.NewBuffer(content)
 	binary.Read(buf, binary.BigEndian, i) //TODO: error handling
 }
 
+// indexContent returns the index content of a key, given the prefix
+// (i.e. everything up to and including the separator before the content).
+// Unlike splitting on the separator, this is safe for binary content.
+func indexContent(key, prefix []byte) []byte {
+	end := len(key) - len(keySeparator) - len(gouuidv6.UUID{})
+	if end < len(prefix) {
+		return nil
+	}
+
+	return key[len(prefix):end]
+}
+
 func stripID(b []byte) []byte {
 	s := bytes.Split(b, []byte(keySeparator))
 	return bytes.Join(s[:len(s)-1], []byte(keySeparator))
@@ -0,0 +1,102 @@
+package tormenta
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/jpincas/gouuidv6"
+)
+
+// Map indexing
+// Each entry of a map field is indexed under the map key, with the value
+// encoded according to its kind, as if it were a field in its own right:
+// i:root:Field.key:value:entityID
+// The map keys are also indexed separately, for HasKey queries:
+// i:root:Field#keys:key:entityID
+// Map keys are used exactly as they are, i.e. they are case sensitive.
+// The values of interface{} maps are indexed according to their actual kind,
+// apart from numbers, which are all indexed as float64. That is how they come back
+// from the serialiser, so the old version of a record, as loaded for deindexing,
+// has the same index keys as when it was saved.
+
+const (
+	mapKeysIndex = "keys"
+
+	ErrFieldNotMap           = "Field %s is not a map"
+	ErrBlankInputHasKeyQuery = "Blank string is not valid input for 'has key' query"
+)
+
+func getMapIndexKeys(v reflect.Value, root []byte, id gouuidv6.UUID, indexName []byte, encoding stringEncoding, opts indexOptions) (keys []indexEntry, err error) {
+	keysIndexName := derivedIndexName(indexName, mapKeysIndex)
+
+	iter := v.MapRange()
+	for iter.Next() {
+		mapKey := fmt.Sprint(iter.Key().Interface())
+		entryIndexName := nestedIndexKeyRoot(indexName, []byte(mapKey))
+
+		content, err := valueIndexContent(iter.Value(), encoding, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		// Nil values are not indexed, but the key still is
+		if content != nil {
+			keys = append(keys, makeIndexEntry(root, id, entryIndexName, content))
+		}
+
+		keys = append(keys, makeIndexEntry(root, id, keysIndexName, []byte(mapKey)))
+	}
+
+	return
+}
+
+// HasKey filters for records where the map field has an entry with the given key
+func (q *Query) HasKey(indexName string, key string) *Query {
+	if key == "" {
+		q.err = errors.New(ErrBlankInputHasKeyQuery)
+		return q
+	}
+
+	indexKind, err := fieldKind(q.target, indexName)
+	if err != nil {
+		q.err = err
+		return q
+	}
+
+	if indexKind != reflect.Map {
+		q.err = fmt.Errorf(ErrFieldNotMap, indexName)
+		return q
+	}
+
+	// Create the filter and add it on
+	q.addFilter(filter{
+		start:          key,
+		end:            key,
+		indexName:      derivedIndexName(toIndexName(indexName), mapKeysIndex),
+		indexKind:      reflect.String,
+		stringEncoding: stringEncoding{caseSensitive: true},
+	})
+
+	return q
+}
+
+// interfaceIndexValue is the value to index for the value of an interface
+func interfaceIndexValue(v reflect.Value) reflect.Value {
+	if isNumericKind(v.Kind()) {
+		return reflect.ValueOf(numericValueToFloat64(v))
+	}
+
+	return v
+}
+
+// interfaceInput is the kind and value to encode query input with,
+// for a field of interface kind, in the same way as interfaceIndexValue
+func interfaceInput(value interface{}) (reflect.Kind, interface{}) {
+	if value == nil {
+		return reflect.Interface, nil
+	}
+
+	v := interfaceIndexValue(reflect.ValueOf(value))
+	return v.Kind(), v.Interface()
+}
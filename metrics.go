@@ -0,0 +1,66 @@
+package tormenta
+
+import "time"
+
+// Operations reported to the metrics hook
+const (
+	OpGet    = "get"
+	OpFind   = "find"
+	OpCount  = "count"
+	OpSum    = "sum"
+	OpPluck  = "pluck"
+	OpSave   = "save"
+	OpDelete = "delete"
+)
+
+// MetricsEvent describes a single completed DB operation
+type MetricsEvent struct {
+	Operation  string
+	EntityType string
+	Duration   time.Duration
+	Results    int
+	Err        error
+}
+
+// Metrics is a hook for exporting DB operation metrics, e.g. to Prometheus.
+// Observe is called synchronously at the end of every operation, so should be quick.
+type Metrics interface {
+	Observe(MetricsEvent)
+}
+
+func (db DB) observe(operation string, entityType []byte, start time.Time, noResults int, err error) {
+	if db.Options.Metrics == nil {
+		return
+	}
+
+	db.Options.Metrics.Observe(MetricsEvent{
+		Operation:  operation,
+		EntityType: string(entityType),
+		Duration:   time.Since(start),
+		Results:    noResults,
+		Err:        err,
+	})
+}
+
+func (q Query) operation() string {
+	if len(q.pluckIndexNames) > 0 {
+		return OpPluck
+	}
+
+	if q.countOnly {
+		return OpCount
+	}
+
+	if len(q.sumIndexName) > 0 && q.sumTarget != nil {
+		return OpSum
+	}
+
+	return OpFind
+}
+
+// report is called at every exit point of a query execution,
+// for metrics and debug logging
+func (q Query) report(start time.Time, noResults int, err error) {
+	q.db.observe(q.operation(), q.keyRoot, start, noResults, err)
+	q.debugLog(start, noResults, err)
+}
@@ -0,0 +1,153 @@
+package tormenta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+type testMetrics struct {
+	events []tormenta.MetricsEvent
+}
+
+func (m *testMetrics) Observe(e tormenta.MetricsEvent) {
+	m.events = append(m.events, e)
+}
+
+type testLogger struct {
+	debugs, warnings, errors []string
+}
+
+func (l *testLogger) Debug(msg string, args ...interface{}) { l.debugs = append(l.debugs, msg) }
+func (l *testLogger) Info(msg string, args ...interface{})  {}
+func (l *testLogger) Warn(msg string, args ...interface{})  { l.warnings = append(l.warnings, msg) }
+func (l *testLogger) Error(msg string, args ...interface{}) { l.errors = append(l.errors, msg) }
+
+func Test_Metrics(t *testing.T) {
+	metrics := &testMetrics{}
+	options := testDBOptions
+	options.Metrics = metrics
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", options)
+	defer db.Close()
+
+	fullStruct := testtypes.FullStruct{IntField: 1, FloatField: 1}
+	db.Save(&fullStruct)
+	db.Get(&testtypes.FullStruct{}, fullStruct.ID)
+
+	var results []testtypes.FullStruct
+	db.Find(&results).Run()
+	db.Find(&results).Count()
+
+	var sum float64
+	db.Find(&results).Sum(&sum, "FloatField")
+
+	db.Delete(&testtypes.FullStruct{}, fullStruct.ID)
+	db.Delete(&testtypes.FullStruct{}, fullStruct.ID)
+
+	expected := []struct {
+		operation string
+		results   int
+		isError   bool
+	}{
+		{tormenta.OpSave, 1, false},
+		{tormenta.OpGet, 1, false},
+		{tormenta.OpFind, 1, false},
+		{tormenta.OpCount, 1, false},
+		{tormenta.OpSum, 1, false},
+		{tormenta.OpDelete, 1, false},
+		{tormenta.OpDelete, 0, true},
+	}
+
+	if len(metrics.events) != len(expected) {
+		t.Fatalf("Testing metrics. Expected %v events, got %v: %v", len(expected), len(metrics.events), metrics.events)
+	}
+
+	for i, e := range expected {
+		event := metrics.events[i]
+
+		if event.Operation != e.operation {
+			t.Errorf("Testing metrics event %v. Expected operation %s, got %s", i, e.operation, event.Operation)
+		}
+
+		if event.EntityType != "fullstruct" {
+			t.Errorf("Testing metrics event %v. Expected entity type fullstruct, got %s", i, event.EntityType)
+		}
+
+		if event.Results != e.results {
+			t.Errorf("Testing metrics event %v (%s). Expected %v results, got %v", i, e.operation, e.results, event.Results)
+		}
+
+		if (event.Err != nil) != e.isError {
+			t.Errorf("Testing metrics event %v (%s). Error was not as expected: %v", i, e.operation, event.Err)
+		}
+	}
+}
+
+func Test_Logger(t *testing.T) {
+	logger := &testLogger{}
+	options := testDBOptions
+	options.Logger = logger
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", options)
+	defer db.Close()
+
+	fullStruct := testtypes.FullStruct{}
+	db.Save(&fullStruct)
+
+	// Debug is off, so nothing should be logged
+	var results []testtypes.FullStruct
+	db.Find(&results).Run()
+	if len(logger.debugs) != 0 {
+		t.Errorf("Testing logger. Expected no debug output when not in debug mode, got %v", logger.debugs)
+	}
+
+	db.Find(&results).Debug().Run()
+	if len(logger.debugs) != 1 {
+		t.Errorf("Testing logger. Expected 1 debug entry, got %v", logger.debugs)
+	}
+
+	db.Find(&results).Debug().Match("IntField", nil).Run()
+	if len(logger.errors) != 1 {
+		t.Errorf("Testing logger. Expected 1 error entry, got %v", logger.errors)
+	}
+
+	// Errors are logged even when not in debug mode
+	db.Find(&results).Match("IntField", nil).Run()
+	if len(logger.errors) != 2 || len(logger.debugs) != 1 {
+		t.Errorf("Testing logger. Expected an error entry without debug mode, got %v", logger.errors)
+	}
+}
+
+func Test_Metrics_OtherReads(t *testing.T) {
+	metrics := &testMetrics{}
+	options := testDBOptions
+	options.Metrics = metrics
+	options.AuditTrail = true
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", options)
+	defer db.Close()
+
+	invoice := testtypes.Invoice{Number: 1}
+	db.Save(&invoice)
+	metrics.events = nil
+
+	db.GetRaw(&testtypes.Invoice{}, invoice.ID)
+	db.History(&[]testtypes.Invoice{}, invoice.ID)
+	db.GetAsOf(&testtypes.Invoice{}, invoice.ID, time.Now())
+	db.AuditTrail(invoice.ID, time.Time{}, time.Time{})
+
+	expectedEntityTypes := []string{"invoice", "invoice", "invoice", "auditentry"}
+	if len(metrics.events) != len(expectedEntityTypes) {
+		t.Fatalf("Testing metrics for other reads. Expected %v events, got %v: %v", len(expectedEntityTypes), len(metrics.events), metrics.events)
+	}
+
+	for i, entityType := range expectedEntityTypes {
+		event := metrics.events[i]
+		if event.Operation != tormenta.OpGet || event.EntityType != entityType || event.Results != 1 || event.Err != nil {
+			t.Errorf("Testing metrics for other reads, event %v. Expected a get of 1 %s, got %v", i, entityType, event)
+		}
+	}
+}
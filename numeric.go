@@ -0,0 +1,145 @@
+package tormenta
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Uniform numeric index encoding.
+// By default, numbers are indexed according to their type (e.g. an int16 is 2 bytes, an int 4 bytes)
+// which means they have to be queried with exactly the same type.
+// With Options.UniformNumericIndex, every number is converted to a float64 and
+// indexed as 8 bytes, with the bits manipulated such that the byte order is the same
+// as the numerical order.  Any numeric type can then be used to query any numeric field,
+// and ints and floats can be range compared.
+// Note that integers beyond 2^53 lose precision in this format.
+
+// indexOptions are the DB options that affect how index keys are built
+type indexOptions struct {
+	uniformNumeric bool
+	nanosecondTime bool
+}
+
+func (db DB) indexOptions() indexOptions {
+	return indexOptions{
+		uniformNumeric: db.Options.UniformNumericIndex,
+		nanosecondTime: db.Options.NanosecondTimeIndex,
+	}
+}
+
+// content returns the index content for a field value
+func (o indexOptions) content(v reflect.Value) interface{} {
+	if o.uniformNumeric && isNumericKind(v.Kind()) {
+		return uniformNumericBytes(numericValueToFloat64(v))
+	}
+
+	if t, ok := asTime(v.Interface()); ok {
+		return o.timeContent(t)
+	}
+
+	return v.Interface()
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+
+	return false
+}
+
+func numericValueToFloat64(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	}
+
+	return v.Float()
+}
+
+// uniformNumericBytes flips the sign bit of positive numbers and all the bits of
+// negative numbers, which puts the IEEE 754 representation in numerical byte order
+func uniformNumericBytes(f float64) []byte {
+	// -0 and 0 should be the same
+	if f == 0 {
+		f = 0
+	}
+
+	bits := math.Float64bits(f)
+	if bits>>63 == 1 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, bits)
+	return b
+}
+
+func uniformNumericValue(b []byte) float64 {
+	if len(b) != 8 {
+		return 0
+	}
+
+	bits := binary.BigEndian.Uint64(b)
+	if bits>>63 == 1 {
+		bits &^= 1 << 63
+	} else {
+		bits = ^bits
+	}
+
+	return math.Float64frombits(bits)
+}
+
+// uniformNumericInput encodes a user provided value of any numeric type (or a string representation of one)
+func uniformNumericInput(value interface{}) ([]byte, error) {
+	if value == nil {
+		return []byte{}, nil
+	}
+
+	if v := reflect.ValueOf(value); isNumericKind(v.Kind()) {
+		return uniformNumericBytes(numericValueToFloat64(v)), nil
+	}
+
+	f, err := interfaceToFloat64(value)
+	return uniformNumericBytes(f), err
+}
+
+// uniformIndexContent returns the content of a uniform numeric index key,
+// which is always the 8 bytes immediately before the ID.
+// Working from the end of the key means we don't have to worry about
+// the separator appearing in the binary content.
+func uniformIndexContent(key []byte) []byte {
+	end := len(key) - len(keySeparator) - len(gouuidv6.UUID{})
+	if end < 8 {
+		return nil
+	}
+
+	return key[end-8 : end]
+}
+
+// quickSumUniform adds the value of a uniform numeric index key to the sum target,
+// whatever its type
+func quickSumUniform(target interface{}, item *badger.Item) {
+	value := uniformNumericValue(uniformIndexContent(item.Key()))
+
+	t := reflect.Indirect(reflect.ValueOf(target))
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		t.SetInt(t.Int() + int64(value))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		t.SetUint(t.Uint() + uint64(value))
+	case reflect.Float32, reflect.Float64:
+		t.SetFloat(t.Float() + value)
+	}
+}
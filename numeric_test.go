@@ -0,0 +1,44 @@
+package tormenta
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func Test_UniformNumericBytes_Order(t *testing.T) {
+	// In ascending order
+	values := []float64{math.Inf(-1), -1e10, -1000.5, -1, -0.001, 0, 0.001, 1, 16, 1000.5, 1e10, math.Inf(1)}
+
+	for i := 1; i < len(values); i++ {
+		a, b := uniformNumericBytes(values[i-1]), uniformNumericBytes(values[i])
+		if bytes.Compare(a, b) >= 0 {
+			t.Errorf("Testing uniform numeric encoding. Expected %v to sort before %v", values[i-1], values[i])
+		}
+	}
+
+	for _, value := range values {
+		if decoded := uniformNumericValue(uniformNumericBytes(value)); decoded != value {
+			t.Errorf("Testing uniform numeric decoding. Expected %v, got %v", value, decoded)
+		}
+	}
+
+	if !bytes.Equal(uniformNumericBytes(math.Copysign(0, -1)), uniformNumericBytes(0)) {
+		t.Error("Testing uniform numeric encoding. Expected -0 and 0 to be encoded the same")
+	}
+}
+
+func Test_UniformNumericInput(t *testing.T) {
+	expected := uniformNumericBytes(16)
+
+	for _, input := range []interface{}{16, int8(16), int16(16), uint64(16), float32(16), 16.0, "16"} {
+		result, err := uniformNumericInput(input)
+		if err != nil {
+			t.Errorf("Testing uniform numeric input %v (%T). Got error %v", input, input, err)
+		}
+
+		if !bytes.Equal(result, expected) {
+			t.Errorf("Testing uniform numeric input %v (%T). Expected %v, got %v", input, input, expected, result)
+		}
+	}
+}
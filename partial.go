@@ -0,0 +1,120 @@
+package tormenta
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// Partial indexes
+// Sparse indexes, on fields tagged `tormenta:"sparse"` (or `tormenta:"omitempty"`),
+// leave out zero values, so only contain the records in which the field is set.
+// Conditional indexes leave out whichever records the record type says they should,
+// by implementing PartialIndexer.
+// Either way, the index doesn't have an entry for every record, so queries which
+// rely on finding the records which aren't in it are refused, and ordering by it
+// (which drops the records which aren't in it) is warned about.
+
+const (
+	ErrSparseIndexZero  = "Index %s is sparse, so zero values can't be searched for - use IsZero instead"
+	ErrPartialIndexZero = "Index %s is conditional, so IsZero and NotZero can't be answered from it"
+
+	warnPartialIndexOrder = "tormenta: ordering by a partial index - records which are not in the index will be missing from the results"
+)
+
+// PartialIndexer is implemented by records which only index some fields under certain conditions,
+// e.g. only indexing the status of orders which are still open.
+// PartialIndexes lists the conditional indexes by name (using the 'Field.SubField' syntax for nested fields),
+// and IndexIf is consulted for each of them whenever the record is indexed.
+type PartialIndexer interface {
+	PartialIndexes() []string
+	IndexIf(indexName string) bool
+}
+
+// indexIf checks a record's conditions for the given index
+func indexIf(entity Record, indexName []byte) bool {
+	partialIndexer, ok := entity.(PartialIndexer)
+	if !ok {
+		return true
+	}
+
+	for _, partialIndex := range partialIndexer.PartialIndexes() {
+		if partialIndex == string(indexName) {
+			return partialIndexer.IndexIf(partialIndex)
+		}
+	}
+
+	return true
+}
+
+func isSparse(field reflect.StructField) bool {
+	return isTaggedWith(field, tormentaTagSparse, tormentaTagOmitEmpty)
+}
+
+// isConditionalIndex reports whether the index is one of the target record type's partial indexes
+func isConditionalIndex(target interface{}, indexName string) bool {
+	t := reflect.TypeOf(target).Elem()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	partialIndexer, ok := reflect.New(t).Interface().(PartialIndexer)
+	if !ok {
+		return false
+	}
+
+	for _, partialIndex := range partialIndexer.PartialIndexes() {
+		if partialIndex == indexName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSparseIndex(target interface{}, indexName string) bool {
+	field, err := structField(target, indexName)
+	return err == nil && isSparse(field)
+}
+
+// checkSparse makes sure that a search on a sparse index doesn't include the zero value,
+// given the encoded start and end of the search
+func (f filter) checkSparse(startBytes, endBytes []byte) error {
+	if !f.sparse || f.indexType == nil {
+		return nil
+	}
+
+	zero, err := f.encodeInput(reflect.Zero(f.indexType).Interface())
+	if err != nil {
+		return err
+	}
+
+	// Reverse queries have had their start and end swapped by now
+	lower, upper, lowerBytes, upperBytes := f.start, f.end, startBytes, endBytes
+	if f.reverse {
+		lower, upper, lowerBytes, upperBytes = upper, lower, upperBytes, lowerBytes
+	}
+
+	includesZero := (lower == nil || bytes.Compare(lowerBytes, zero) <= 0) &&
+		(upper == nil || bytes.Compare(zero, upperBytes) <= 0)
+
+	if includesZero {
+		return fmt.Errorf(ErrSparseIndexZero, f.indexName)
+	}
+
+	return nil
+}
+
+// warnIfPartialOrder warns, through the logger if there is one,
+// that ordering by a partial index leaves records out
+func (q *Query) warnIfPartialOrder() {
+	logger := q.db.Options.Logger
+	if logger == nil || len(q.orderByIndexName) == 0 {
+		return
+	}
+
+	indexName := string(q.orderByIndexName)
+	if isSparseIndex(q.target, indexName) || isConditionalIndex(q.target, indexName) {
+		logger.Warn(warnPartialIndexOrder, "entity", string(q.keyRoot), "index", indexName)
+	}
+}
@@ -0,0 +1,339 @@
+package tormenta
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Query planning for AND queries with more than one filter.
+// Rather than running every filter over its full index range and intersecting
+// the results, we estimate how many keys each filter will touch, run only the most
+// selective one in full and then check the remaining filters against the (hopefully small)
+// candidate set, stopping as soon as there is nothing left to check.
+
+// Plan strategies
+const (
+	// Run the filter over its full index range
+	PlanStrategyScan = "scan"
+	// Look up the exact index key for each candidate ID
+	PlanStrategySeek = "seek"
+	// Iterate the filter range, but only keep candidate IDs
+	PlanStrategyValidate = "validate"
+)
+
+// The most keys the planner counts for a filter.
+// Filters with more keys than this are all estimated at just over it,
+// so that estimating is never more expensive than a bounded scan.
+const planCountLimit = 1000
+
+type queryPlan struct {
+	steps []planStep
+}
+
+type planStep struct {
+	filter   filter
+	estimate int
+	strategy string
+
+	// The filter's IDs, if they were all collected while estimating,
+	// and the keys that were scanned to do so
+	ids      idList
+	complete bool
+	scanned  int
+}
+
+func (q Query) shouldUsePlanner() bool {
+	return len(q.filters) > 1 && !isOr(q.idsCombinator)
+}
+
+// plan prepares each filter and orders them by estimated cardinality
+func (q Query) plan(txn *badger.Txn) (queryPlan, error) {
+	p := queryPlan{}
+
+	// best is the lowest estimate so far - there is no need to carry on
+	// counting keys for a filter once it has gone past that
+	best := planCountLimit
+
+	for _, f := range q.filters {
+		if !f.prepared {
+			if err := f.prepare(); err != nil {
+				return p, err
+			}
+		}
+
+		// Keys scanned while counting are recorded on a step of their own,
+		// so that they can be added to the profile if the IDs are reused
+		counting := &PlanStep{}
+		f.step = counting
+		estimate, ids, complete, err := f.count(txn, best)
+		f.step = nil
+		if err != nil {
+			return p, err
+		}
+
+		if estimate < best {
+			best = estimate
+		}
+
+		p.steps = append(p.steps, planStep{
+			filter:   f,
+			estimate: estimate,
+			ids:      ids,
+			complete: complete,
+			scanned:  counting.KeysScanned,
+		})
+	}
+
+	sort.SliceStable(p.steps, func(i, j int) bool {
+		return p.steps[i].estimate < p.steps[j].estimate
+	})
+
+	for i := range p.steps {
+		if i == 0 {
+			p.steps[i].strategy = PlanStrategyScan
+		} else if p.steps[i].filter.canSeek() {
+			p.steps[i].strategy = PlanStrategySeek
+		} else {
+			p.steps[i].strategy = PlanStrategyValidate
+		}
+	}
+
+	return p, nil
+}
+
+func (p queryPlan) execute(txn *badger.Txn, profile *Plan) (idList, error) {
+	var candidates idList
+
+	for i, step := range p.steps {
+		var err error
+
+		t := time.Now()
+		step.filter.step = profile.addFilterStep(step.filter, step.strategy, step.estimate)
+
+		if i == 0 && step.complete {
+			// Already collected while estimating
+			step.filter.step.scannedKeys(step.scanned)
+			candidates = step.ids.unique()
+		} else if i == 0 {
+			candidates, err = step.filter.queryIDs(txn)
+			candidates = candidates.unique()
+		} else {
+			candidates, err = step.filter.validate(txn, candidates)
+		}
+
+		if err != nil {
+			return idList{}, err
+		}
+
+		step.filter.step.finish(t, len(candidates))
+
+		// Short circuit - nothing left to validate
+		if len(candidates) == 0 {
+			return candidates, nil
+		}
+	}
+
+	return candidates, nil
+}
+
+// Filter helpers for the planner
+
+// canSeek reports whether the filter can be checked for a given ID
+// by looking up a single index key
+func (f filter) canSeek() bool {
+	if f.isWordsSearch() {
+		for _, wordFilter := range f.wordFilters {
+			if !wordFilter.canSeek() {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return f.isExactIndexMatchSearch() && !f.isStartsWithQuery
+}
+
+// count tallies the keys in the filter's range.
+// If max is greater than 0, counting stops as soon as it is exceeded.
+// If counting gets to the end of the range, the IDs are returned as well,
+// with complete set, so that they don't need to be scanned for again.
+func (f *filter) count(txn *badger.Txn, max int) (n int, ids idList, complete bool, err error) {
+	if f.isWordsSearch() {
+		n, err = f.countWords(txn, max)
+		return
+	}
+
+	// There is no cheap way to estimate a substring search,
+	// so we just run it
+	if f.isContainsQuery() {
+		ids, err = f.queryContainsIDs(txn)
+		return len(ids), ids, err == nil, err
+	}
+
+	if f.isZeroQuery() {
+		ids, err = f.queryZeroIDs(txn)
+		return len(ids), ids, err == nil, err
+	}
+
+	it := txn.NewIterator(f.getIteratorOptions())
+	defer it.Close()
+
+	for it.Seek(f.seekFrom); f.endIteration(it, 0); it.Next() {
+		f.step.scanned()
+		id := extractID(it.Item().Key())
+		if !f.isExactIndexMatchSearch() {
+			if keyIsOutsideDateRange(id, f.from, f.to) {
+				continue
+			}
+		}
+
+		n++
+		if max > 0 && n > max {
+			return n, nil, false, nil
+		}
+
+		ids = append(ids, id)
+	}
+
+	return n, ids, true, nil
+}
+
+// validate returns the candidate IDs which also satisfy this filter,
+// preserving the order of the candidates
+func (f *filter) validate(txn *badger.Txn, candidates idList) (idList, error) {
+	if f.isWordsSearch() {
+		return f.validateWords(txn, candidates)
+	}
+
+	if f.isContainsQuery() {
+		return f.validateContains(txn, candidates)
+	}
+
+	if f.isZeroQuery() {
+		return f.validateZero(txn, candidates)
+	}
+
+	if f.canSeek() {
+		indexContent, err := f.encodeInput(f.start)
+		if err != nil {
+			return idList{}, err
+		}
+
+		var ids idList
+		for _, id := range candidates {
+			f.step.scanned()
+			_, err := txn.Get(newIndexMatchKey(f.keyRoot, f.indexName, indexContent, id).bytes())
+			if err == badger.ErrKeyNotFound {
+				continue
+			} else if err != nil {
+				return idList{}, err
+			}
+
+			ids = append(ids, id)
+		}
+
+		return ids, nil
+	}
+
+	remaining := map[gouuidv6.UUID]bool{}
+	for _, id := range candidates {
+		remaining[id] = true
+	}
+
+	found := map[gouuidv6.UUID]bool{}
+
+	it := txn.NewIterator(f.getIteratorOptions())
+	defer it.Close()
+
+	for it.Seek(f.seekFrom); f.endIteration(it, 0) && len(remaining) > 0; it.Next() {
+		f.step.scanned()
+		id := extractID(it.Item().Key())
+		if remaining[id] {
+			delete(remaining, id)
+			found[id] = true
+		}
+	}
+
+	var ids idList
+	for _, id := range candidates {
+		if found[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// countWords estimates a 'contains' filter: matching all the words can't give
+// more than the rarest word, matching any of them can't give more than all the words together
+func (f *filter) countWords(txn *badger.Txn, max int) (n int, err error) {
+	for i, wordFilter := range f.wordFilters {
+		if f.matchAnyWord {
+			wordCount, _, _, err := wordFilter.count(txn, max)
+			if err != nil {
+				return 0, err
+			}
+
+			n += wordCount
+			if max > 0 && n > max {
+				return n, nil
+			}
+
+			continue
+		}
+
+		wordCount, _, _, err := wordFilter.count(txn, max)
+		if err != nil {
+			return 0, err
+		}
+
+		if i == 0 || wordCount < n {
+			n = wordCount
+		}
+	}
+
+	return n, nil
+}
+
+func (f *filter) validateWords(txn *badger.Txn, candidates idList) (idList, error) {
+	if !f.matchAnyWord {
+		for _, wordFilter := range f.wordFilters {
+			wordFilter.step = f.step
+
+			var err error
+			candidates, err = wordFilter.validate(txn, candidates)
+			if err != nil || len(candidates) == 0 {
+				return candidates, err
+			}
+		}
+
+		return candidates, nil
+	}
+
+	found := map[gouuidv6.UUID]bool{}
+	for _, wordFilter := range f.wordFilters {
+		wordFilter.step = f.step
+
+		ids, err := wordFilter.validate(txn, candidates)
+		if err != nil {
+			return idList{}, err
+		}
+
+		for _, id := range ids {
+			found[id] = true
+		}
+	}
+
+	var ids idList
+	for _, id := range candidates {
+		if found[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
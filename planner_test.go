@@ -0,0 +1,220 @@
+package tormenta_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_Planner_And(t *testing.T) {
+	var fullStructs []tormenta.Record
+
+	for i := 0; i < 100; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:    i,
+			StringField: getStringForPlanner(i),
+			BoolField:   i%2 == 0,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName string
+		query    func(q *tormenta.Query) *tormenta.Query
+		expected int
+	}{
+		{"match + match", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("StringField", "rare").Match("BoolField", true)
+		}, 10},
+		{"match + range", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("StringField", "rare").Range("IntField", 0, 49)
+		}, 5},
+		{"range + match + match", func(q *tormenta.Query) *tormenta.Query {
+			return q.Range("IntField", 0, 49).Match("StringField", "common").Match("BoolField", false)
+		}, 25},
+		{"match + starts with", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("BoolField", true).StartsWith("StringField", "com")
+		}, 40},
+		{"no results from most selective filter", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("StringField", "nothing").Range("IntField", 0, 99)
+		}, 0},
+		{"no results after validation", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("StringField", "rare").Range("IntField", 200, 300)
+		}, 0},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		n, err := testCase.query(db.Find(&results)).Run()
+		if err != nil {
+			t.Errorf("Testing %s. Got error %v", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v, got %v", testCase.testName, testCase.expected, n)
+		}
+
+		c, err := testCase.query(db.Find(&results)).Count()
+		if err != nil {
+			t.Errorf("Testing %s (count). Got error %v", testCase.testName, err)
+		}
+
+		if c != testCase.expected {
+			t.Errorf("Testing %s (count). Expected %v, got %v", testCase.testName, testCase.expected, c)
+		}
+	}
+}
+
+func Test_Planner_Explain(t *testing.T) {
+	var fullStructs []tormenta.Record
+
+	for i := 0; i < 100; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:    i,
+			StringField: getStringForPlanner(i),
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	results := []testtypes.FullStruct{}
+	plan, err := db.Find(&results).
+		Range("IntField", 0, 49).
+		Match("StringField", "rare").
+		Explain()
+
+	if err != nil {
+		t.Fatalf("Testing explain. Got error %v", err)
+	}
+
+	if len(plan.Steps) != 3 {
+		t.Fatalf("Testing explain. Expected 3 steps, got %v:\n%s", len(plan.Steps), plan)
+	}
+
+	// The rare string should be run first, even though it was added second
+	first := plan.Steps[0]
+	if first.Strategy != tormenta.PlanStrategyScan || first.Index != "StringField" {
+		t.Errorf("Testing explain. Expected the string match to be scanned first, got %s", first)
+	}
+
+	if first.Estimate != 10 || first.KeysScanned != 10 || first.IDsEmitted != 10 {
+		t.Errorf("Testing explain. Expected the first step to estimate, scan and emit 10, got %s", first)
+	}
+
+	second := plan.Steps[1]
+	if second.Strategy != tormenta.PlanStrategyValidate || second.Index != "IntField" {
+		t.Errorf("Testing explain. Expected the int range to be validated second, got %s", second)
+	}
+
+	if second.IDsEmitted != 5 {
+		t.Errorf("Testing explain. Expected the second step to emit 5, got %s", second)
+	}
+
+	fetch := plan.Steps[2]
+	if fetch.Stage != tormenta.PlanStageFetch || fetch.RecordsFetched != 5 {
+		t.Errorf("Testing explain. Expected 5 records to be fetched, got %s", fetch)
+	}
+
+	if plan.Totals.Results != 5 || plan.Totals.RecordsFetched != 5 || plan.Totals.Steps != 3 {
+		t.Errorf("Testing explain. Totals not as expected: %s", plan.Totals)
+	}
+
+	if len(results) != 5 {
+		t.Errorf("Testing explain. Expected 5 results to be set on the target, got %v", len(results))
+	}
+}
+
+func Test_Planner_CountLimit(t *testing.T) {
+	var invoices []tormenta.Record
+
+	for i := 0; i < 1500; i++ {
+		invoices = append(invoices, &testtypes.Invoice{Number: i, Amount: 1})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.SaveIndividually(invoices...)
+
+	results := []testtypes.Invoice{}
+	plan, err := db.Find(&results).
+		Match("Amount", 1).
+		Match("Number", 7).
+		Explain()
+
+	if err != nil {
+		t.Fatalf("Testing planner count limit. Got error %v", err)
+	}
+
+	if len(results) != 1 || results[0].Number != 7 {
+		t.Errorf("Testing planner count limit. Expected invoice 7, got %v", results)
+	}
+
+	// Counting the common amount stops well before the end of its index
+	for _, step := range plan.Steps {
+		if step.Index == "Amount" && (step.Estimate <= 1 || step.Estimate >= 1500) {
+			t.Errorf("Testing planner count limit. Expected the amount estimate to be capped, got %s", step)
+		}
+	}
+
+	first := plan.Steps[0]
+	if first.Index != "Number" || first.KeysScanned != 1 || first.IDsEmitted != 1 {
+		t.Errorf("Testing planner count limit. Expected the number match to be scanned first, got %s", first)
+	}
+}
+
+func Test_Explain_OrderAndSum(t *testing.T) {
+	var fullStructs []tormenta.Record
+
+	for i := 0; i < 20; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:   i,
+			FloatField: float64(i),
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	results := []testtypes.FullStruct{}
+	plan, err := db.Find(&results).OrderBy("IntField").Limit(5).Explain()
+	if err != nil {
+		t.Fatalf("Testing explain with order. Got error %v", err)
+	}
+
+	stages := []string{}
+	for _, step := range plan.Steps {
+		stages = append(stages, step.Stage)
+	}
+
+	expectedStages := []string{tormenta.PlanStageAll, tormenta.PlanStageOrder, tormenta.PlanStageFetch}
+	if strings.Join(stages, ",") != strings.Join(expectedStages, ",") {
+		t.Errorf("Testing explain with order. Expected stages %v, got %v", expectedStages, stages)
+	}
+
+	if plan.Steps[1].IDsEmitted != 5 || len(plan.Steps[1].SeekFrom) == 0 {
+		t.Errorf("Testing explain with order. Order step not as expected: %s", plan.Steps[1])
+	}
+
+	var sum float64
+	q := db.Find(&results).Range("IntField", 0, 9)
+	q.Sum(&sum, "FloatField")
+	if sum != 45 {
+		t.Errorf("Testing sum after planner changes. Expected 45, got %v", sum)
+	}
+}
+
+func getStringForPlanner(i int) string {
+	if i%10 == 0 {
+		return "rare"
+	}
+
+	return "common"
+}
@@ -0,0 +1,296 @@
+package tormenta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Covered queries
+// Pluck returns the values of some indexes for the results of a query,
+// reconstructing them from the index keys, so that records don't have to be
+// fetched or unmarshalled at all.  This is ideal for list views which only need a few fields.
+// Values come back as they are indexed, so:
+// - strings are lower cased (and folded) unless the field is case sensitive
+// - times are to the second, unless Options.NanosecondTimeIndex is set
+// - the members of slices come back in index order, with duplicates removed
+// - pointer fields come back as the values they point to, except for big numbers,
+// which always come back as pointers
+// - records which aren't in an index (nil pointers, sparse and conditional indexes)
+// don't have an entry for it
+// Full text, split string, map and struct fields can't be reconstructed, so can't be plucked,
+// and neither can fields which aren't indexed.
+
+const (
+	// PluckIDKey is the key under which the ID of each record is returned
+	PluckIDKey = "ID"
+
+	ErrNoPluckIndexes    = "At least one index is required to pluck"
+	ErrIndexNotPluckable = "Index %s can't be plucked, as its values can't be reconstructed from the index"
+)
+
+var typeUUID = reflect.TypeOf(gouuidv6.UUID{})
+
+type pluckIndex struct {
+	name      string
+	indexName []byte
+	t         reflect.Type
+	multiple  bool
+}
+
+// Pluck runs the query and returns, for each result in order, a map of the values of the given indexes,
+// keyed by index name, plus the record ID under PluckIDKey.
+func (q *Query) Pluck(indexNames ...string) ([]map[string]interface{}, error) {
+	t := time.Now()
+	q.pluckIndexNames = indexNames
+
+	results, err := q.pluck()
+	q.report(t, len(results), err)
+	return results, err
+}
+
+func (q *Query) pluck() ([]map[string]interface{}, error) {
+	if len(q.pluckIndexNames) == 0 {
+		return nil, errors.New(ErrNoPluckIndexes)
+	}
+
+	var indexes []pluckIndex
+	for _, name := range q.pluckIndexNames {
+		index, err := q.pluckIndex(name)
+		if err != nil {
+			return nil, err
+		}
+
+		indexes = append(indexes, index)
+	}
+
+	txn := q.db.KV.NewTransaction(false)
+	defer txn.Discard()
+
+	ids, err := q.resultIDs(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]interface{}, len(ids))
+	positions := map[gouuidv6.UUID]int{}
+	for i, id := range ids {
+		results[i] = map[string]interface{}{PluckIDKey: id}
+		positions[id] = i
+	}
+
+	if len(ids) == 0 {
+		return results, nil
+	}
+
+	opts := q.db.indexOptions()
+	for _, index := range indexes {
+		if err := q.pluckValues(txn, index, results, positions, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+// pluckValues sets the values of an index on the results.
+// The values of an index come before the IDs in its keys, so there is no way to seek
+// to the key for a particular ID - instead we iterate the index, but stop as soon as
+// every result has its value.  Records can have several values in indexes of slices,
+// and none in sparse and conditional indexes, so those have to be iterated to the end.
+func (q *Query) pluckValues(txn *badger.Txn, index pluckIndex, results []map[string]interface{}, positions map[gouuidv6.UUID]int, opts indexOptions) error {
+	prefix := newIndexKey(q.keyRoot, index.indexName, nil).bytes()
+	remaining := len(results)
+
+	options := badger.DefaultIteratorOptions
+	options.PrefetchValues = false
+
+	it := txn.NewIterator(options)
+	defer it.Close()
+
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		key := it.Item().Key()
+		i, ok := positions[extractID(key)]
+		if !ok {
+			continue
+		}
+
+		value := decodeIndexContent(indexContent(key, prefix), index.t, opts)
+		if !index.multiple {
+			results[i][index.name] = value.Interface()
+
+			remaining--
+			if remaining == 0 {
+				return nil
+			}
+
+			continue
+		}
+
+		values, ok := results[i][index.name]
+		if !ok {
+			values = reflect.MakeSlice(reflect.SliceOf(index.t), 0, 1).Interface()
+		}
+
+		results[i][index.name] = reflect.Append(reflect.ValueOf(values), value).Interface()
+	}
+
+	return nil
+}
+
+// pluckIndex works out the type that the values of an index should be decoded to,
+// and checks that they can be
+func (q *Query) pluckIndex(name string) (pluckIndex, error) {
+	field, err := structField(q.target, name)
+	if err != nil {
+		return pluckIndex{}, err
+	}
+
+	index := pluckIndex{
+		name:      name,
+		indexName: toIndexName(name),
+		t:         field.Type,
+	}
+
+	// Members of slices and arrays are indexed individually,
+	// as are the fields of nested slices of structs
+	if isMultipleIndexType(index.t) {
+		index.t = derefType(index.t.Elem())
+		index.multiple = true
+	}
+
+	path := strings.Split(name, indexKeySeparator)
+	for i := 1; i < len(path); i++ {
+		if parent := fieldType(q.target, strings.Join(path[:i], indexKeySeparator)); parent != nil && isMultipleIndexType(parent) {
+			index.multiple = true
+		}
+	}
+
+	if !isPluckableType(index.t) || isTaggedWith(field, tormentaTagFullText, tormentaTagSplit, tormentaTagNoIndex, tormentaTagNoSave) {
+		return pluckIndex{}, fmt.Errorf(ErrIndexNotPluckable, name)
+	}
+
+	return index, nil
+}
+
+func isMultipleIndexType(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t != typeUUID && !isCustomIndexType(t)
+}
+
+func isPluckableType(t reflect.Type) bool {
+	if isCustomIndexType(t) {
+		return isBigNumberType(derefType(t)) || reflect.PtrTo(derefType(t)).Implements(typeIndexValueDecoder)
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool:
+		return true
+	case reflect.Array:
+		return t == typeUUID
+	case reflect.Struct:
+		return t.ConvertibleTo(typeTime)
+	}
+
+	return isNumericKind(t.Kind())
+}
+
+// decodeIndexContent decodes index content into a value of the given type,
+// reversing the encoding used when indexing
+func decodeIndexContent(content []byte, t reflect.Type, opts indexOptions) reflect.Value {
+	switch {
+	case isCustomIndexType(t):
+		return decodeCustomIndexContent(content, t)
+
+	case t.Kind() == reflect.Struct && t.ConvertibleTo(typeTime):
+		return reflect.ValueOf(decodeTimeContent(content, opts)).Convert(t)
+
+	case opts.uniformNumeric && isNumericKind(t.Kind()):
+		return reflect.ValueOf(uniformNumericValue(content)).Convert(t)
+
+	case t == typeUUID:
+		// UUIDs are indexed as their string representations,
+		// so decode them the same way as from JSON
+		var id gouuidv6.UUID
+		json.Unmarshal([]byte(strconv.Quote(string(content))), &id)
+		return reflect.ValueOf(id)
+
+	case t.Kind() == reflect.String:
+		return reflect.ValueOf(string(content)).Convert(t)
+	}
+
+	// Fixed width types are decoded into the type they were written as,
+	// with variable length ints and uints written as 32 bits
+	var decoded reflect.Value
+	switch t.Kind() {
+	case reflect.Int:
+		decoded = reflect.New(reflect.TypeOf(int32(0)))
+	case reflect.Uint:
+		decoded = reflect.New(reflect.TypeOf(uint32(0)))
+	default:
+		decoded = reflect.New(basicTypes[t.Kind()])
+	}
+
+	decodeIndexValue(content, decoded.Interface())
+	return decoded.Elem().Convert(t)
+}
+
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    typeBool,
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: typeFloat,
+}
+
+func decodeTimeContent(content []byte, opts indexOptions) time.Time {
+	var unix int64
+	decodeIndexValue(content, &unix)
+
+	if !opts.nanosecondTime {
+		return time.Unix(unix, 0)
+	}
+
+	// The zero time is clamped to the minimum when indexed
+	if unix == math.MinInt64 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, unix)
+}
+
+// decodeCustomIndexContent decodes the content of a big number or IndexValueDecoder index.
+// Big numbers are returned as pointers, as they are normally used.
+func decodeCustomIndexContent(content []byte, t reflect.Type) reflect.Value {
+	switch derefType(t) {
+	case typeBigInt:
+		return reflect.ValueOf(decodeBigInt(content))
+	case typeBigFloat:
+		return reflect.ValueOf(decodeBigFloat(content))
+	case typeBigRat:
+		r, _ := decodeBigFloat(content).Rat(nil)
+		if r == nil {
+			r = new(big.Rat)
+		}
+
+		return reflect.ValueOf(r)
+	}
+
+	decoded := reflect.New(derefType(t))
+	decodeIndexValue(content, decoded.Interface())
+	return decoded.Elem()
+}
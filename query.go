@@ -48,6 +48,12 @@ type Query struct {
 	sumIndexName []byte
 	sumTarget    interface{}
 
+	// Indexes to pluck values from, instead of fetching records
+	pluckIndexNames []string
+
+	// JSON paths to project raw results down to
+	selectFields [][]string
+
 	// Pass-through context
 	ctx map[string]interface{}
 
@@ -55,6 +61,9 @@ type Query struct {
 	filters    []filter
 	basicQuery *basicQuery
 
+	// Ranked search, e.g. full text or fuzzy
+	ranker ranker
+
 	// Logical ID combinator
 	idsCombinator func(...idList) idList
 
@@ -62,6 +71,9 @@ type Query struct {
 	prepared bool
 
 	debug bool
+
+	// Execution profile, only set when the query is being explained
+	profile *Plan
 }
 
 func (q Query) Compare(cq Query) bool {
@@ -103,7 +115,8 @@ func (q *Query) addFilter(f filter) {
 func (q Query) shouldApplyLimitOffsetToFilter() bool {
 	// We only pass the limit/offset to a filter if
 	// there is only 1 filter AND there is no order by index
-	return len(q.filters) == 1 && len(q.orderByIndexName) == 0
+	// AND the results are not going to be ranked by a search
+	return len(q.filters) == 1 && len(q.orderByIndexName) == 0 && q.ranker == nil
 }
 
 func (q Query) shouldApplyLimitOffsetToBasicQuery() bool {
@@ -119,6 +132,7 @@ func (q *Query) prepareQuery() {
 		q.filters[i].reverse = q.reverse
 		q.filters[i].from = q.from
 		q.filters[i].to = q.to
+		q.filters[i].indexOptions = q.db.indexOptions()
 
 		if q.shouldApplyLimitOffsetToFilter() {
 			q.filters[i].limit = q.limit
@@ -149,8 +163,6 @@ func (q *Query) queryIDs(txn *badger.Txn) (idList, error) {
 		q.prepareQuery()
 	}
 
-	var allResults []idList
-
 	// If during the query planning and preparation,
 	// something has gone wrong and an error has been set on the query,
 	// we'll return right here and now
@@ -158,22 +170,59 @@ func (q *Query) queryIDs(txn *badger.Txn) (idList, error) {
 		return idList{}, q.err
 	}
 
+	// Full text and fuzzy searches produce a ranked list,
+	// which is then combined with any filters
+	if q.ranker != nil {
+		return q.rankedIDs(txn)
+	}
+
+	return q.filterIDs(txn)
+}
+
+func (q *Query) filterIDs(txn *badger.Txn) (idList, error) {
+	var allResults []idList
+
+	// AND queries with multiple filters go through the planner,
+	// which runs the most selective filter first and then just checks
+	// the remaining filters against its results
+	if q.shouldUsePlanner() {
+		p, err := q.plan(txn)
+		if err != nil {
+			return idList{}, err
+		}
+
+		return p.execute(txn, q.profile)
+	}
+
 	if len(q.filters) > 0 {
 		// FOR WHEN THERE ARE INDEX FILTERS
 		// We process them serially at the moment, becuase Badger can only support 1 iterator
 		// per transaction.  If that limitation is ever removed, we could do this in parallel
 		for _, filter := range q.filters {
+			t := time.Now()
+			filter.step = q.profile.addFilterStep(filter, PlanStrategyScan, 0)
+
 			thisFilterResults, err := filter.queryIDs(txn)
 			// If preparing any of the filters results in an error,
 			// rerturn it now
 			if err != nil {
 				return idList{}, err
 			}
+
+			filter.step.finish(t, len(thisFilterResults))
 			allResults = append(allResults, thisFilterResults)
 		}
 	} else {
 		// FOR WHEN THERE ARE NO INDEX FILTERS
-		allResults = []idList{q.basicQuery.queryIDs(txn)}
+		t := time.Now()
+		q.basicQuery.step = q.profile.addStep(PlanStep{
+			Stage:    PlanStageAll,
+			Strategy: PlanStrategyScan,
+		})
+
+		ids := q.basicQuery.queryIDs(txn)
+		q.basicQuery.step.finish(t, len(ids))
+		allResults = []idList{ids}
 	}
 
 	// Combine the results from multiple filters,
@@ -185,51 +234,20 @@ func (q *Query) queryIDs(txn *badger.Txn) (idList, error) {
 func (q *Query) execute() (int, error) {
 	// Start time for debugging, if required
 	t := time.Now()
+	q.warnIfPartialOrder()
 
 	txn := q.db.KV.NewTransaction(false)
 	defer txn.Discard()
 
-	finalIDList, err := q.queryIDs(txn)
+	finalIDList, err := q.resultIDs(txn)
 	if err != nil {
-		q.debugLog(t, 0, err)
+		q.report(t, 0, err)
 		return 0, err
 	}
 
-	// TODO: more conditions to restrict when this is necessary
-	if len(q.orderByIndexName) > 0 {
-		indexKind, err := fieldKind(q.target, string(q.orderByIndexName))
-		if err != nil {
-			q.debugLog(t, 0, err)
-			return 0, err
-		}
-
-		is := indexSearch{
-			idsToSearchFor: finalIDList,
-			reverse:        q.reverse,
-			limit:          q.limit,
-			keyRoot:        q.keyRoot,
-			indexName:      q.orderByIndexName,
-			indexKind:      indexKind,
-			offset:         q.offset,
-		}
-
-		// If we are doing a quicksum and the sum index is the same
-		// as the order index, we can take advantage of this index
-		// iteration to do the sum
-		if len(q.sumIndexName) > 0 && q.sumTarget != nil {
-			if string(q.sumIndexName) == string(q.orderByIndexName) {
-				is.sumIndexName = q.sumIndexName
-				is.sumTarget = q.sumTarget
-			}
-		}
-
-		// This will order and apply limit/offset
-		finalIDList = is.execute(txn)
-	}
-
 	// For count-only, there's nothing more to do
 	if q.countOnly {
-		q.debugLog(t, len(finalIDList), nil)
+		q.report(t, len(finalIDList), nil)
 		return len(finalIDList), nil
 	}
 
@@ -243,7 +261,7 @@ func (q *Query) execute() (int, error) {
 
 			indexKind, err := fieldKind(q.target, string(q.sumIndexName))
 			if err != nil {
-				q.debugLog(t, 0, err)
+				q.report(t, 0, err)
 				return 0, err
 			}
 
@@ -254,17 +272,24 @@ func (q *Query) execute() (int, error) {
 				keyRoot:        q.keyRoot,
 				indexName:      q.sumIndexName,
 				indexKind:      indexKind,
+				uniformNumeric: q.db.Options.UniformNumericIndex,
 				offset:         q.offset,
 				sumIndexName:   q.sumIndexName,
 				sumTarget:      q.sumTarget,
+				step: q.profile.addStep(PlanStep{
+					Stage: PlanStageSum,
+					Index: string(q.sumIndexName),
+				}),
 			}
 
-			is.execute(txn)
+			sumStart := time.Now()
+			sumIDs := is.execute(txn)
+			is.step.finish(sumStart, len(sumIDs))
 		}
 
 		// Now, whether the quicksum was on the same index as order,
 		// or any other index, we will have the result in the target, so we can return now
-		q.debugLog(t, len(finalIDList), nil)
+		q.report(t, len(finalIDList), nil)
 		return len(finalIDList), nil
 	}
 
@@ -273,35 +298,93 @@ func (q *Query) execute() (int, error) {
 		// For 'first' queries, we should check that there is at least 1 record found
 		// before trying to set it
 		if len(finalIDList) == 0 {
-			q.debugLog(t, 0, nil)
+			q.report(t, 0, nil)
 			return 0, nil
 		}
 
 		// db.get ususally takes a 'Record', so we need to set a new one up
 		// and then set the result of get to the target aftwards
+		fetchStart := time.Now()
+		fetchStep := q.profile.addStep(PlanStep{Stage: PlanStageFetch})
+
 		record := newRecord(q.target)
 		id := finalIDList[0]
 		if found, err := q.db.get(txn, record, q.ctx, id); err != nil {
-			q.debugLog(t, 0, err)
+			q.report(t, 0, err)
 			return 0, err
 		} else if !found {
 			err := fmt.Errorf("Could not retrieve record with id: %v", id)
-			q.debugLog(t, 0, err)
+			q.report(t, 0, err)
 			return 0, err
 		}
 
+		fetchStep.fetched(fetchStart, 1)
 		setSingleResultOntoTarget(q.target, record)
-		q.debugLog(t, 1, nil)
+		q.report(t, 1, nil)
 		return 1, nil
 	}
 
 	// Otherwise we just get the records and return
+	fetchStart := time.Now()
+	fetchStep := q.profile.addStep(PlanStep{Stage: PlanStageFetch})
+
 	n, err := q.db.getIDsWithContext(txn, q.target, q.ctx, finalIDList...)
 	if err != nil {
-		q.debugLog(t, 0, err)
+		q.report(t, 0, err)
 		return 0, err
 	}
 
-	q.debugLog(t, n, nil)
+	fetchStep.fetched(fetchStart, n)
+
+	q.report(t, n, nil)
 	return n, nil
 }
+
+// resultIDs works out the IDs of the records matching the query,
+// ordered and with the limit and offset applied
+func (q *Query) resultIDs(txn *badger.Txn) (idList, error) {
+	finalIDList, err := q.queryIDs(txn)
+	if err != nil {
+		return idList{}, err
+	}
+
+	// TODO: more conditions to restrict when this is necessary
+	if len(q.orderByIndexName) > 0 {
+		indexKind, err := fieldKind(q.target, string(q.orderByIndexName))
+		if err != nil {
+			return idList{}, err
+		}
+
+		is := indexSearch{
+			idsToSearchFor: finalIDList,
+			reverse:        q.reverse,
+			limit:          q.limit,
+			keyRoot:        q.keyRoot,
+			indexName:      q.orderByIndex(),
+			indexKind:      indexKind,
+			uniformNumeric: q.db.Options.UniformNumericIndex,
+			offset:         q.offset,
+			step: q.profile.addStep(PlanStep{
+				Stage: PlanStageOrder,
+				Index: string(q.orderByIndex()),
+			}),
+		}
+
+		// If we are doing a quicksum and the sum index is the same
+		// as the order index, we can take advantage of this index
+		// iteration to do the sum
+		if len(q.sumIndexName) > 0 && q.sumTarget != nil {
+			if string(q.sumIndexName) == string(q.orderByIndexName) {
+				is.sumIndexName = q.sumIndexName
+				is.sumTarget = q.sumTarget
+			}
+		}
+
+		// This will order and apply limit/offset
+		orderStart := time.Now()
+		finalIDList = is.execute(txn)
+		is.step.finish(orderStart, len(finalIDList))
+	}
+
+	return finalIDList, nil
+}
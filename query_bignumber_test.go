@@ -0,0 +1,102 @@
+package tormenta_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_BigNumberIndex(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	// 10^20 is well beyond int64
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil)
+
+	var fullStructs []tormenta.Record
+	for i := -5; i <= 5; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			BigIntField:   new(big.Int).Mul(big.NewInt(int64(i)), scale),
+			BigFloatField: big.NewFloat(float64(i) * 1.25),
+			BigRatField:   big.NewRat(int64(i), 4),
+			DecimalField:  testtypes.Decimal(i * 150),
+		})
+	}
+
+	// Nil big numbers - should not be indexed,
+	// but the decimal is indexed as 0
+	fullStructs = append(fullStructs, &testtypes.FullStruct{})
+
+	db.Save(fullStructs...)
+
+	minusTwo := new(big.Int).Mul(big.NewInt(-2), scale)
+
+	testCases := []struct {
+		testName   string
+		indexName  string
+		start, end interface{}
+		expected   int
+	}{
+		{"big int, big int bounds", "BigIntField", minusTwo, new(big.Int).Add(scale, big.NewInt(1)), 4},
+		{"big int, big int value bound", "BigIntField", *minusTwo, nil, 8},
+		{"big int, int bounds", "BigIntField", -1, 1, 1},
+		{"big int, string bounds", "BigIntField", "0", "300000000000000000000", 4},
+		{"big float, float bounds", "BigFloatField", -2.5, 2.5, 5},
+		{"big float, big float bounds", "BigFloatField", big.NewFloat(0.1), nil, 5},
+		{"big float, string bounds", "BigFloatField", nil, "-3.75", 3},
+		{"big rat, rat bounds", "BigRatField", big.NewRat(-2, 3), big.NewRat(1, 3), 4},
+		{"big rat, float bounds", "BigRatField", 0.5, 2, 4},
+		{"decimal, decimal bounds", "DecimalField", testtypes.Decimal(-300), testtypes.Decimal(0), 4},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		n, err := db.Find(&results).Range(testCase.indexName, testCase.start, testCase.end).Run()
+		if err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+
+	// Exact match
+	results := []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Match("BigRatField", big.NewRat(2, 8)).Run(); n != 1 {
+		t.Errorf("Testing match on a big rat field. Expected 1 result, got %v", n)
+	}
+
+	results = []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Match("DecimalField", testtypes.Decimal(750)).Run(); n != 1 {
+		t.Errorf("Testing match on a decimal field. Expected 1 result, got %v", n)
+	}
+
+	// Order - negatives first
+	results = []testtypes.FullStruct{}
+	db.Find(&results).OrderBy("BigIntField").Run()
+	if len(results) != 11 || results[0].BigIntField.Cmp(new(big.Int).Mul(big.NewInt(-5), scale)) != 0 || results[10].BigIntField.Cmp(new(big.Int).Mul(big.NewInt(5), scale)) != 0 {
+		t.Errorf("Testing order by big int field. Results were not in order")
+	}
+
+	// Sums
+	bigIntSum := new(big.Int)
+	db.Find(&results).Range("BigIntField", 0, nil).Sum(bigIntSum, "BigIntField")
+	if expected := new(big.Int).Mul(big.NewInt(15), scale); bigIntSum.Cmp(expected) != 0 {
+		t.Errorf("Testing sum of big int field. Expected %v, got %v", expected, bigIntSum)
+	}
+
+	bigFloatSum := new(big.Float)
+	db.Find(&results).Range("BigFloatField", 0, nil).Sum(bigFloatSum, "BigFloatField")
+	if bigFloatSum.Cmp(big.NewFloat(18.75)) != 0 {
+		t.Errorf("Testing sum of big float field. Expected 18.75, got %v", bigFloatSum)
+	}
+
+	bigRatSum := new(big.Rat)
+	db.Find(&results).Range("BigRatField", 0, nil).Sum(bigRatSum, "BigRatField")
+	if bigRatSum.Cmp(big.NewRat(15, 4)) != 0 {
+		t.Errorf("Testing sum of big rat field. Expected 15/4, got %v", bigRatSum)
+	}
+}
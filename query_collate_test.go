@@ -0,0 +1,82 @@
+package tormenta_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_Collation(t *testing.T) {
+	names := []string{"Zoe", "Émile", "Anton", "Ärger", "Bernd"}
+
+	var fullStructs []tormenta.Record
+	for _, name := range names {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			StringField:   name,
+			CollatedField: name,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	namesOf := func(results []testtypes.FullStruct) (names []string) {
+		for _, result := range results {
+			names = append(names, result.CollatedField)
+		}
+
+		return
+	}
+
+	testCases := []struct {
+		testName  string
+		indexName string
+		reverse   bool
+		expected  []string
+	}{
+		{"bytes order", "StringField", false, []string{"Anton", "Bernd", "Zoe", "Ärger", "Émile"}},
+		{"collated order", "CollatedField", false, []string{"Anton", "Ärger", "Bernd", "Émile", "Zoe"}},
+		{"collated order - reversed", "CollatedField", true, []string{"Zoe", "Émile", "Bernd", "Ärger", "Anton"}},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		q := db.Find(&results).OrderBy(testCase.indexName)
+		if testCase.reverse {
+			q.Reverse()
+		}
+
+		if _, err := q.Run(); err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if fmt.Sprint(namesOf(results)) != fmt.Sprint(testCase.expected) {
+			t.Errorf("Testing %s. Expected %v, got %v", testCase.testName, testCase.expected, namesOf(results))
+		}
+	}
+
+	// Range follows the collation too
+	results := []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Range("StringField", "a", "c").Run(); n != 2 {
+		t.Errorf("Testing range by bytes. Expected 2 results, got %v", n)
+	}
+
+	results = []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Range("CollatedField", "a", "c").Run(); n != 3 {
+		t.Errorf("Testing collated range. Expected 3 results, got %v", n)
+	}
+
+	results = []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Range("CollatedField", "d", nil).Run(); n != 2 {
+		t.Errorf("Testing collated range with no end. Expected 2 results, got %v", n)
+	}
+
+	// Whilst exact matches still work
+	results = []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Match("CollatedField", "émile").Run(); n != 1 {
+		t.Errorf("Testing match on collated field. Expected 1 result, got %v", n)
+	}
+}
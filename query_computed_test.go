@@ -0,0 +1,87 @@
+package tormenta_test
+
+import (
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_ComputedIndexes(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	domains := []string{"example.com", "Test.org"}
+
+	var orders []tormenta.Record
+	for i := 1; i <= 10; i++ {
+		orders = append(orders, &testtypes.Order{
+			Qty:   i,
+			Price: 2.5,
+			Email: "customer@" + domains[i%2],
+		})
+	}
+
+	db.Save(orders...)
+
+	testCases := []struct {
+		testName string
+		query    func(*tormenta.Query) *tormenta.Query
+		expected int
+	}{
+		{"match computed string", func(q *tormenta.Query) *tormenta.Query { return q.Match("EmailDomain", "test.org") }, 5},
+		{"match computed string - case insensitive", func(q *tormenta.Query) *tormenta.Query { return q.Match("EmailDomain", "EXAMPLE.COM") }, 5},
+		{"starts with computed string", func(q *tormenta.Query) *tormenta.Query { return q.StartsWith("EmailDomain", "test") }, 5},
+		{"match computed number", func(q *tormenta.Query) *tormenta.Query { return q.Match("Total", 10.0) }, 1},
+		{"range computed number", func(q *tormenta.Query) *tormenta.Query { return q.Range("Total", 5.0, 12.5) }, 4},
+		{"computed and real index", func(q *tormenta.Query) *tormenta.Query {
+			return q.Range("Total", 5.0, 12.5).Match("EmailDomain", "example.com")
+		}, 2},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.Order{}
+		n, err := testCase.query(db.Find(&results)).Run()
+		if err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+
+	// Order
+	results := []testtypes.Order{}
+	db.Find(&results).OrderBy("Total").Reverse().Run()
+	if len(results) != 10 || results[0].Qty != 10 || results[9].Qty != 1 {
+		t.Errorf("Testing order by computed index. Results were not in order")
+	}
+
+	// Sum
+	var sum float64
+	db.Find(&results).Range("Total", 20.0, nil).Sum(&sum, "Total")
+	if sum != 67.5 {
+		t.Errorf("Testing sum of computed index. Expected 67.5, got %v", sum)
+	}
+
+	// Updating a record recomputes its indexes
+	order := orders[0].(*testtypes.Order)
+	order.Qty = 100
+	db.Save(order)
+
+	results = []testtypes.Order{}
+	if n, _ := db.Find(&results).Match("Total", 2.5).Run(); n != 0 {
+		t.Errorf("Testing computed index after update. Expected old value to be deindexed, got %v results", n)
+	}
+
+	results = []testtypes.Order{}
+	if n, _ := db.Find(&results).Match("Total", 250.0).Run(); n != 1 {
+		t.Errorf("Testing computed index after update. Expected 1 result, got %v", n)
+	}
+
+	// Names which are neither fields nor computed indexes are still errors
+	if _, err := db.Find(&results).Match("NoSuchIndex", 1).Run(); err == nil {
+		t.Error("Testing unknown index. Expected an error")
+	}
+}
@@ -0,0 +1,103 @@
+package tormenta_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_Fuzzy(t *testing.T) {
+	names := []string{"Smith", "Smyth", "Smithers", "Jones", "Smit", "Schmidt"}
+
+	var fullStructs []tormenta.Record
+	for i, name := range names {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:     i,
+			StringField:  name,
+			TrigramField: name,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName      string
+		term          string
+		maxEdits      int
+		expected      []int
+		expectedError error
+	}{
+		{"blank term", "", 1, []int{}, errors.New(tormenta.ErrBlankInputFuzzy)},
+		{"negative edits", "smith", -1, []int{}, errors.New(tormenta.ErrNegativeFuzzyEdit)},
+		{"exact only", "smith", 0, []int{0}, nil},
+		{"1 edit", "smith", 1, []int{0, 1, 4}, nil},
+		{"1 edit - case insensitive", "SMITH", 1, []int{0, 1, 4}, nil},
+		{"2 edits - ranked by distance", "smyth", 2, []int{1, 0, 4}, nil},
+		{"3 edits", "smith", 3, []int{0, 1, 4, 2}, nil},
+		{"4 edits", "schmit", 4, []int{5, 4, 0, 1}, nil},
+		{"no matches", "williams", 2, []int{}, nil},
+	}
+
+	// The results should be the same whether or not the field has a trigram index
+	for _, indexName := range []string{"StringField", "TrigramField"} {
+		for _, testCase := range testCases {
+			results := []testtypes.FullStruct{}
+			n, err := db.Find(&results).Fuzzy(indexName, testCase.term, testCase.maxEdits).Run()
+
+			if testCase.expectedError != nil && err == nil {
+				t.Errorf("Testing %s (%s). Expected error [%v] but got none", testCase.testName, indexName, testCase.expectedError)
+			}
+
+			if testCase.expectedError == nil && err != nil {
+				t.Errorf("Testing %s (%s). Didn't expect error [%v]", testCase.testName, indexName, err)
+			}
+
+			if n != len(testCase.expected) {
+				t.Errorf("Testing %s (%s). Expected %v results, got %v", testCase.testName, indexName, len(testCase.expected), n)
+				continue
+			}
+
+			for i, result := range results {
+				if result.IntField != testCase.expected[i] {
+					t.Errorf("Testing %s (%s). Expected result %v to be %v, got %v", testCase.testName, indexName, i, testCase.expected[i], result.IntField)
+				}
+			}
+		}
+	}
+
+	// The trigram index means fewer keys are scanned
+	results := []testtypes.FullStruct{}
+	scanPlan, _ := db.Find(&results).Fuzzy("StringField", "smith", 1).Explain()
+	results = []testtypes.FullStruct{}
+	trigramPlan, _ := db.Find(&results).Fuzzy("TrigramField", "jones", 1).Explain()
+
+	if trigramPlan.Steps[0].Strategy != tormenta.PlanStrategySeek {
+		t.Errorf("Testing fuzzy search plan. Expected the trigram index to be used, got %v", trigramPlan.Steps[0])
+	}
+
+	if scanPlan.Steps[0].KeysScanned != len(names) {
+		t.Errorf("Testing fuzzy search plan. Expected every key to be scanned without trigrams, got %v", scanPlan.Steps[0].KeysScanned)
+	}
+
+	// Combined with a filter
+	results = []testtypes.FullStruct{}
+	n, _ := db.Find(&results).Fuzzy("TrigramField", "smith", 1).Range("IntField", 1, 5).Run()
+	if n != 2 || results[0].IntField != 1 || results[1].IntField != 4 {
+		t.Errorf("Testing fuzzy search with filter. Expected records 1 and 4, got %v", results)
+	}
+
+	// Only one ranked search per query
+	results = []testtypes.FullStruct{}
+	if _, err := db.Find(&results).Fuzzy("StringField", "smith", 1).Fuzzy("TrigramField", "smith", 1).Run(); err == nil {
+		t.Error("Testing multiple fuzzy searches. Expected error but got none")
+	}
+
+	// Non string fields
+	if _, err := db.Find(&results).Fuzzy("IntField", "1", 1).Run(); err == nil {
+		t.Error("Testing fuzzy search on non string field. Expected error but got none")
+	}
+}
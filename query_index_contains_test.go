@@ -0,0 +1,157 @@
+package tormenta_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_IndexQuery_Contains(t *testing.T) {
+	descriptions := []string{
+		"red wool sweater",
+		"Blue wool sweater",
+		"red cotton shirt",
+		"the red scarf",
+		"green wool socks",
+	}
+
+	var fullStructs []tormenta.Record
+	for i, description := range descriptions {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:          i,
+			MultipleWordField: description,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName      string
+		words         []string
+		matchAny      bool
+		reverse       bool
+		expected      []int
+		expectedError error
+	}{
+		{"no words", []string{}, false, false, nil, errors.New(tormenta.ErrNoWordsContainsQuery)},
+		{"only non-content words", []string{"the", "on"}, false, false, nil, errors.New(tormenta.ErrNoWordsContainsQuery)},
+		{"all - single word", []string{"sweater"}, false, false, []int{0, 1}, nil},
+		{"all - phrase", []string{"red wool sweater"}, false, false, []int{0}, nil},
+		{"all - separate words", []string{"red", "wool", "sweater"}, false, false, []int{0}, nil},
+		{"all - case and non-content words", []string{"the RED", "Sweater"}, false, false, []int{0}, nil},
+		{"all - missing word", []string{"red", "socks"}, false, false, []int{}, nil},
+		{"all - reversed", []string{"wool"}, false, true, []int{4, 1, 0}, nil},
+		{"any - single word", []string{"red"}, true, false, []int{0, 2, 3}, nil},
+		{"any - phrase", []string{"scarf socks"}, true, false, []int{3, 4}, nil},
+		{"any - overlapping words", []string{"wool", "sweater"}, true, false, []int{0, 1, 4}, nil},
+		{"any - no matches", []string{"trousers", "hat"}, true, false, []int{}, nil},
+		{"any - reversed", []string{"shirt", "sweater"}, true, true, []int{2, 1, 0}, nil},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+
+		q := db.Find(&results)
+		if testCase.matchAny {
+			q.ContainsAny("MultipleWordField", testCase.words...)
+		} else {
+			q.ContainsAll("MultipleWordField", testCase.words...)
+		}
+
+		if testCase.reverse {
+			q.Reverse()
+		}
+
+		n, err := q.Run()
+
+		if testCase.expectedError != nil && err == nil {
+			t.Errorf("Testing %s. Expected error [%v] but got none", testCase.testName, testCase.expectedError)
+		}
+
+		if testCase.expectedError == nil && err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != len(testCase.expected) {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, len(testCase.expected), n)
+			continue
+		}
+
+		for i, result := range results {
+			if result.IntField != testCase.expected[i] {
+				t.Errorf("Testing %s. Expected result %v to be %v, got %v", testCase.testName, i, testCase.expected[i], result.IntField)
+			}
+		}
+	}
+}
+
+func Test_IndexQuery_Contains_Combined(t *testing.T) {
+	var fullStructs []tormenta.Record
+	for i := 0; i < 20; i++ {
+		description := "blue cotton shirt"
+		if i%2 == 0 {
+			description = "red wool sweater"
+		}
+
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:          i,
+			MultipleWordField: description,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	// Combined with other filters - goes through the planner
+	results := []testtypes.FullStruct{}
+	n, err := db.Find(&results).ContainsAll("MultipleWordField", "red wool").Range("IntField", 5, 12).Run()
+	if err != nil {
+		t.Errorf("Testing contains all with range. Got error %v", err)
+	}
+
+	if n != 4 {
+		t.Errorf("Testing contains all with range. Expected 4 results, got %v", n)
+	}
+
+	results = []testtypes.FullStruct{}
+	n, _ = db.Find(&results).Range("IntField", 0, 1).ContainsAny("MultipleWordField", "shirt", "socks").Run()
+	if n != 1 || results[0].IntField != 1 {
+		t.Errorf("Testing contains any with range. Expected record 1 only, got %v", results)
+	}
+
+	// Or
+	n, _ = db.Find(&results).ContainsAll("MultipleWordField", "blue shirt").Or().Match("IntField", 0).Run()
+	if n != 11 {
+		t.Errorf("Testing contains all, or match. Expected 11 results, got %v", n)
+	}
+
+	// Limit and offset on a single contains filter
+	results = []testtypes.FullStruct{}
+	n, _ = db.Find(&results).ContainsAll("MultipleWordField", "sweater").Offset(2).Limit(3).Run()
+	if n != 3 || results[0].IntField != 4 {
+		t.Errorf("Testing contains all with limit and offset. Expected 3 results starting at 4, got %v", results)
+	}
+}
+
+func Test_IndexQuery_Contains_NotSplit(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(&testtypes.FullStruct{StringField: "red wool"})
+
+	expectedError := fmt.Errorf(tormenta.ErrFieldNotSplit, "StringField")
+
+	results := []testtypes.FullStruct{}
+	if _, err := db.Find(&results).ContainsAll("StringField", "red wool").Run(); err == nil || err.Error() != expectedError.Error() {
+		t.Errorf("Testing contains all on a field that isn't split. Expected error %v, got %v", expectedError, err)
+	}
+
+	if _, err := db.Find(&results).ContainsAny("StringField", "red").Run(); err == nil || err.Error() != expectedError.Error() {
+		t.Errorf("Testing contains any on a field that isn't split. Expected error %v, got %v", expectedError, err)
+	}
+}
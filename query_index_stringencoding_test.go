@@ -0,0 +1,115 @@
+package tormenta_test
+
+import (
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_IndexQuery_CaseSensitive(t *testing.T) {
+	skus := []string{"ABC-1", "abc-1", "Abc-2", "XYZ-1"}
+
+	var fullStructs []tormenta.Record
+	for _, sku := range skus {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			StringField:        sku,
+			CaseSensitiveField: sku,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName             string
+		indexName            string
+		match, startsWith    string
+		rangeStart, rangeEnd string
+		expected             int
+	}{
+		{"match - case insensitive", "StringField", "abc-1", "", "", "", 2},
+		{"match - case sensitive - upper", "CaseSensitiveField", "ABC-1", "", "", "", 1},
+		{"match - case sensitive - lower", "CaseSensitiveField", "abc-1", "", "", "", 1},
+		{"match - case sensitive - no match", "CaseSensitiveField", "aBC-1", "", "", "", 0},
+		{"starts with - case insensitive", "StringField", "", "abc", "", "", 3},
+		{"starts with - case sensitive", "CaseSensitiveField", "", "Abc", "", "", 1},
+		{"range - case insensitive", "StringField", "", "", "a", "b", 3},
+		{"range - case sensitive", "CaseSensitiveField", "", "", "A", "B", 2},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		q := db.Find(&results)
+
+		if testCase.match != "" {
+			q.Match(testCase.indexName, testCase.match)
+		} else if testCase.startsWith != "" {
+			q.StartsWith(testCase.indexName, testCase.startsWith)
+		} else {
+			q.Range(testCase.indexName, testCase.rangeStart, testCase.rangeEnd)
+		}
+
+		n, err := q.Run()
+		if err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+}
+
+func Test_IndexQuery_Fold(t *testing.T) {
+	names := []string{"José", "Jose", "JOSÉ", "Zoë", "Ångström"}
+
+	var fullStructs []tormenta.Record
+	for _, name := range names {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			StringField: name,
+			FoldField:   name,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName          string
+		indexName         string
+		match, startsWith string
+		expected          int
+	}{
+		{"not folded - accented", "StringField", "josé", "", 2},
+		{"not folded - unaccented", "StringField", "jose", "", 1},
+		{"folded - accented", "FoldField", "josé", "", 3},
+		{"folded - unaccented", "FoldField", "jose", "", 3},
+		{"folded - upper case", "FoldField", "JOSE", "", 3},
+		{"folded - diaeresis", "FoldField", "zoe", "", 1},
+		{"folded - starts with", "FoldField", "", "angs", 1},
+		{"not folded - starts with", "StringField", "", "angs", 0},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		q := db.Find(&results)
+
+		if testCase.match != "" {
+			q.Match(testCase.indexName, testCase.match)
+		} else {
+			q.StartsWith(testCase.indexName, testCase.startsWith)
+		}
+
+		n, err := q.Run()
+		if err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package tormenta_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+var orderReferences = []string{"ORD-2019-0042", "ORD-2019-1142", "INV-2020-0042", "ORD-2020-7777", "RET-2019-0001"}
+
+func Test_IndexQuery_EndsWith(t *testing.T) {
+	var fullStructs []tormenta.Record
+	for i, ref := range orderReferences {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:      i,
+			ReversedField: ref,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName      string
+		endsWith      string
+		reverse       bool
+		expected      int
+		expectedError error
+	}{
+		{"blank string", "", false, 0, errors.New(tormenta.ErrBlankInputEndsWithQuery)},
+		{"no match", "9999", false, 0, nil},
+		{"single match", "7777", false, 1, nil},
+		{"case insensitive", "ord-2020-7777", false, 1, nil},
+		{"wide match", "42", false, 3, nil},
+		{"narrower match", "0042", false, 2, nil},
+		{"whole string", "RET-2019-0001", false, 1, nil},
+		{"longer than any value", "XORD-2019-0042", false, 0, nil},
+		{"wide match - reversed", "42", true, 3, nil},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+
+		q := db.Find(&results).EndsWith("ReversedField", testCase.endsWith)
+		if testCase.reverse {
+			q.Reverse()
+		}
+
+		n, err := q.Run()
+
+		if testCase.expectedError != nil && err == nil {
+			t.Errorf("Testing %s. Expected error [%v] but got none", testCase.testName, testCase.expectedError)
+		}
+
+		if testCase.expectedError == nil && err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+
+	// Not tagged
+	results := []testtypes.FullStruct{}
+	if _, err := db.Find(&results).EndsWith("StringField", "42").Run(); err == nil {
+		t.Error("Testing ends with on a field without a reversed index. Expected error but got none")
+	}
+}
+
+func Test_IndexQuery_Contains_Substring(t *testing.T) {
+	var fullStructs []tormenta.Record
+	for i, ref := range orderReferences {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:     i,
+			TrigramField: ref,
+		})
+	}
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName      string
+		contains      string
+		reverse       bool
+		expected      []int
+		expectedError error
+	}{
+		{"blank string", "", false, []int{}, errors.New(tormenta.ErrBlankInputContainsQuery)},
+		{"no match", "9999", false, []int{}, nil},
+		{"middle of string", "2019", false, []int{0, 1, 4}, nil},
+		{"case insensitive", "inv", false, []int{2}, nil},
+		{"across separator", "9-11", false, []int{1}, nil},
+		{"trigrams present but not together", "0042-ORD", false, []int{}, nil},
+		{"single character", "7", false, []int{3}, nil},
+		{"two characters", "t-", false, []int{4}, nil},
+		{"end of string", "0042", false, []int{0, 2}, nil},
+		{"whole string", "ord-2020-7777", false, []int{3}, nil},
+		{"middle of string - reversed", "2019", true, []int{4, 1, 0}, nil},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+
+		q := db.Find(&results).Contains("TrigramField", testCase.contains)
+		if testCase.reverse {
+			q.Reverse()
+		}
+
+		n, err := q.Run()
+
+		if testCase.expectedError != nil && err == nil {
+			t.Errorf("Testing %s. Expected error [%v] but got none", testCase.testName, testCase.expectedError)
+		}
+
+		if testCase.expectedError == nil && err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != len(testCase.expected) {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, len(testCase.expected), n)
+			continue
+		}
+
+		for i, result := range results {
+			if result.IntField != testCase.expected[i] {
+				t.Errorf("Testing %s. Expected result %v to be %v, got %v", testCase.testName, i, testCase.expected[i], result.IntField)
+			}
+		}
+	}
+
+	// Combined with another filter
+	results := []testtypes.FullStruct{}
+	n, _ := db.Find(&results).Contains("TrigramField", "2019").Range("IntField", 1, 4).Run()
+	if n != 2 {
+		t.Errorf("Testing contains with range. Expected 2 results, got %v", n)
+	}
+
+	// Not tagged
+	results = []testtypes.FullStruct{}
+	if _, err := db.Find(&results).Contains("StringField", "42").Run(); err == nil {
+		t.Error("Testing contains on a field without a trigram index. Expected error but got none")
+	}
+}
@@ -0,0 +1,95 @@
+package tormenta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_IndexValuer(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	var fullStructs []tormenta.Record
+	for i := -5; i <= 5; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			DecimalField: testtypes.Decimal(i * 1000),
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName   string
+		start, end interface{}
+		expected   int
+		expectErr  bool
+	}{
+		{"decimal bounds", testtypes.Decimal(-2000), testtypes.Decimal(2000), 5, false},
+		{"decimal pointer bounds", new(testtypes.Decimal), nil, 6, false},
+		{"int bounds - converted", -1000, 1000, 3, false},
+		{"float bounds - converted", 999.9, 5000.0, 5, false},
+		{"string bounds - can't be converted", "-1000", "1000", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		n, err := db.Find(&results).Range("DecimalField", testCase.start, testCase.end).Run()
+		if !testCase.expectErr && err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		} else if testCase.expectErr && err == nil {
+			t.Errorf("Testing %s. Expected error but got none", testCase.testName)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+
+	// Order - negatives first
+	results := []testtypes.FullStruct{}
+	db.Find(&results).OrderBy("DecimalField").Run()
+	if len(results) != 11 || results[0].DecimalField != -5000 || results[10].DecimalField != 5000 {
+		t.Errorf("Testing order by IndexValuer field. Results were not in order")
+	}
+
+	// Sum, decoding the index values
+	var sum testtypes.Decimal
+	db.Find(&results).Range("DecimalField", 0, nil).Sum(&sum, "DecimalField")
+	if sum != 15000 {
+		t.Errorf("Testing sum of IndexValuer field. Expected 15000, got %v", sum)
+	}
+}
+
+func Test_DefinedTime_Index(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var fullStructs []tormenta.Record
+	for i := 0; i < 10; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			DefinedDateField: testtypes.DefinedDate(start.AddDate(0, 0, i)),
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	results := []testtypes.FullStruct{}
+	n, err := db.Find(&results).Range("DefinedDateField", start.AddDate(0, 0, 2), start.AddDate(0, 0, 4)).Run()
+	if err != nil {
+		t.Errorf("Testing range on defined time field. Didn't expect error [%v]", err)
+	}
+
+	if n != 3 {
+		t.Errorf("Testing range on defined time field. Expected 3 results, got %v", n)
+	}
+
+	results = []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Match("DefinedDateField", testtypes.DefinedDate(start)).Run(); n != 1 {
+		t.Errorf("Testing match on defined time field with defined time input. Expected 1 result, got %v", n)
+	}
+}
@@ -0,0 +1,160 @@
+package tormenta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_MapIndex(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	colours := []string{"Red", "green", "blue"}
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var fullStructs []tormenta.Record
+	for i := 0; i < 9; i++ {
+		stringMap := map[string]string{"colour": colours[i%3]}
+		if i%2 == 0 {
+			stringMap["Size"] = "large"
+		}
+
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			StringMapField: stringMap,
+			IntMapField:    map[string]int{"score": i},
+			FloatMapField:  map[string]float64{"weight": float64(i) / 2},
+			DateMapField:   map[string]time.Time{"created": start.AddDate(0, 0, i)},
+		})
+	}
+
+	// A record with no maps
+	fullStructs = append(fullStructs, &testtypes.FullStruct{})
+
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName string
+		query    func(*tormenta.Query) *tormenta.Query
+		expected int
+	}{
+		{"match string value - case insensitive", func(q *tormenta.Query) *tormenta.Query { return q.Match("StringMapField.colour", "red") }, 3},
+		{"match string value - other key", func(q *tormenta.Query) *tormenta.Query { return q.Match("StringMapField.Size", "LARGE") }, 5},
+		{"match string value - no such key", func(q *tormenta.Query) *tormenta.Query { return q.Match("StringMapField.shape", "red") }, 0},
+		{"starts with string value", func(q *tormenta.Query) *tormenta.Query { return q.StartsWith("StringMapField.colour", "gr") }, 3},
+		{"match int value", func(q *tormenta.Query) *tormenta.Query { return q.Match("IntMapField.score", 4) }, 1},
+		{"range int value", func(q *tormenta.Query) *tormenta.Query { return q.Range("IntMapField.score", 1, 5) }, 5},
+		{"range float value", func(q *tormenta.Query) *tormenta.Query { return q.Range("FloatMapField.weight", 1.0, nil) }, 7},
+		{"range date value", func(q *tormenta.Query) *tormenta.Query {
+			return q.Range("DateMapField.created", start.AddDate(0, 0, 3), start.AddDate(0, 0, 4))
+		}, 2},
+		{"has key", func(q *tormenta.Query) *tormenta.Query { return q.HasKey("StringMapField", "colour") }, 9},
+		{"has key - some", func(q *tormenta.Query) *tormenta.Query { return q.HasKey("StringMapField", "Size") }, 5},
+		{"has key - case sensitive", func(q *tormenta.Query) *tormenta.Query { return q.HasKey("StringMapField", "size") }, 0},
+		{"has key and match", func(q *tormenta.Query) *tormenta.Query {
+			return q.HasKey("StringMapField", "Size").Match("StringMapField.colour", "red")
+		}, 2},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		n, err := testCase.query(db.Find(&results)).Run()
+		if err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+
+	// Order by a map entry
+	results := []testtypes.FullStruct{}
+	db.Find(&results).OrderBy("IntMapField.score").Reverse().Run()
+	if len(results) != 9 || results[0].IntMapField["score"] != 8 || results[8].IntMapField["score"] != 0 {
+		t.Errorf("Testing order by map entry. Results were not in order")
+	}
+
+	// Sum of a map entry
+	var sum int
+	db.Find(&results).Sum(&sum, "IntMapField.score")
+	if sum != 36 {
+		t.Errorf("Testing sum of map entry. Expected 36, got %v", sum)
+	}
+}
+
+func Test_MapIndex_InterfaceValues(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	colours := []string{"Red", "green", "blue"}
+
+	var fullStructs []tormenta.Record
+	for i := 0; i < 9; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			AnyMapField: map[string]interface{}{
+				"size":   i,
+				"weight": float64(i) / 2,
+				"colour": colours[i%3],
+				"large":  i%2 == 0,
+			},
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName string
+		query    func(*tormenta.Query) *tormenta.Query
+		expected int
+	}{
+		{"match int value", func(q *tormenta.Query) *tormenta.Query { return q.Match("AnyMapField.size", 4) }, 1},
+		{"match int value with a float", func(q *tormenta.Query) *tormenta.Query { return q.Match("AnyMapField.size", 4.0) }, 1},
+		{"range int value", func(q *tormenta.Query) *tormenta.Query { return q.Range("AnyMapField.size", 1, 5) }, 5},
+		{"range float value", func(q *tormenta.Query) *tormenta.Query { return q.Range("AnyMapField.weight", 1.0, nil) }, 7},
+		{"match string value", func(q *tormenta.Query) *tormenta.Query { return q.Match("AnyMapField.colour", "red") }, 3},
+		{"match bool value", func(q *tormenta.Query) *tormenta.Query { return q.Match("AnyMapField.large", true) }, 5},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		n, err := testCase.query(db.Find(&results)).Run()
+		if err != nil {
+			t.Errorf("Testing interface map - %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing interface map - %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+
+	// Updating a record leaves no trace of the old value in the index
+	record := fullStructs[4].(*testtypes.FullStruct)
+	record.AnyMapField["size"] = 40
+	db.Save(record)
+
+	results := []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Match("AnyMapField.size", 4).Run(); n != 0 {
+		t.Errorf("Testing interface map after update. Expected old value not to match, got %v", n)
+	}
+
+	if n, _ := db.Find(&results).Match("AnyMapField.size", 40).Run(); n != 1 {
+		t.Errorf("Testing interface map after update. Expected new value to match, got %v", n)
+	}
+}
+
+func Test_MapIndex_HasKeyErrors(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	results := []testtypes.FullStruct{}
+	if _, err := db.Find(&results).HasKey("StringField", "colour").Run(); err == nil {
+		t.Error("Testing has key on a non-map field. Expected an error")
+	}
+
+	if _, err := db.Find(&results).HasKey("StringMapField", "").Run(); err == nil {
+		t.Error("Testing has key with a blank key. Expected an error")
+	}
+}
@@ -0,0 +1,75 @@
+package tormenta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func nanosecondTimeOptions() tormenta.Options {
+	options := testDBOptions
+	options.NanosecondTimeIndex = true
+	return options
+}
+
+func Test_NanosecondTimeIndex(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	// All within the same second
+	base := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	var fullStructs []tormenta.Record
+	for i := 0; i < 10; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:  i,
+			DateField: base.Add(time.Duration(i) * 100 * time.Millisecond),
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	start, end := base.Add(150*time.Millisecond), base.Add(450*time.Millisecond)
+
+	// Second precision can't tell them apart
+	results := []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Range("DateField", start, end).Run(); n != 10 {
+		t.Errorf("Testing range on second precision time index. Expected 10 results, got %v", n)
+	}
+
+	// Switch on nanosecond precision and migrate
+	nanosecondDB := &tormenta.DB{KV: db.KV, Options: nanosecondTimeOptions()}
+	if err := nanosecondDB.RebuildIndexes(&testtypes.FullStruct{}); err != nil {
+		t.Fatalf("Testing rebuilding indexes. Got error %v", err)
+	}
+
+	results = []testtypes.FullStruct{}
+	n, err := nanosecondDB.Find(&results).Range("DateField", start, end).Run()
+	if err != nil {
+		t.Errorf("Testing range on nanosecond time index. Didn't expect error [%v]", err)
+	}
+
+	if n != 3 {
+		t.Errorf("Testing range on nanosecond time index. Expected 3 results, got %v", n)
+	}
+
+	results = []testtypes.FullStruct{}
+	if n, _ := nanosecondDB.Find(&results).Match("DateField", base.Add(700*time.Millisecond)).Run(); n != 1 {
+		t.Errorf("Testing match on nanosecond time index. Expected 1 result, got %v", n)
+	}
+
+	// Order - saved in ascending order, so check the reverse
+	results = []testtypes.FullStruct{}
+	nanosecondDB.Find(&results).OrderBy("DateField").Reverse().Run()
+	if len(results) != 10 {
+		t.Fatalf("Testing order by nanosecond time index. Expected 10 results, got %v", len(results))
+	}
+
+	for i, result := range results {
+		if result.IntField != 9-i {
+			t.Errorf("Testing order by nanosecond time index. Expected IntField %v at position %v, got %v", 9-i, i, result.IntField)
+		}
+	}
+}
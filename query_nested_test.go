@@ -0,0 +1,83 @@
+package tormenta_test
+
+import (
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_NestedSliceAndPointerIndex(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	skus := []string{"apple", "banana", "cherry"}
+
+	var fullStructs []tormenta.Record
+	for i := 0; i < 6; i++ {
+		i := i
+		sku := skus[i%3]
+
+		fullStruct := &testtypes.FullStruct{
+			StructSliceField: []testtypes.MyStruct{
+				{StructStringField: sku, StructIntField: i},
+				{StructStringField: "shared", StructIntField: i * 10},
+			},
+			StructPtrSliceField: []*testtypes.MyStruct{nil, {StructStringField: sku}},
+		}
+
+		// Only set the pointers on some of the records
+		if i%2 == 0 {
+			fullStruct.IntPtrField = &i
+			fullStruct.StringPtrField = &sku
+			fullStruct.StructPtrField = &testtypes.MyStruct{StructIntField: i}
+		}
+
+		fullStructs = append(fullStructs, fullStruct)
+	}
+
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName string
+		query    func(*tormenta.Query) *tormenta.Query
+		expected int
+	}{
+		{"match on slice of structs", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("StructSliceField.StructStringField", "apple")
+		}, 2},
+		{"match on slice of structs - all members", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("StructSliceField.StructStringField", "shared")
+		}, 6},
+		{"range on slice of structs", func(q *tormenta.Query) *tormenta.Query {
+			return q.Range("StructSliceField.StructIntField", 30, 40)
+		}, 2},
+		{"match on slice of struct pointers", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("StructPtrSliceField.StructStringField", "cherry")
+		}, 2},
+		{"match on int pointer", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("IntPtrField", 2)
+		}, 1},
+		{"range on int pointer - nils not indexed", func(q *tormenta.Query) *tormenta.Query {
+			return q.Range("IntPtrField", 0, 100)
+		}, 3},
+		{"match on string pointer", func(q *tormenta.Query) *tormenta.Query {
+			return q.Match("StringPtrField", "APPLE")
+		}, 1},
+		{"range on nested struct pointer", func(q *tormenta.Query) *tormenta.Query {
+			return q.Range("StructPtrField.StructIntField", 1, 4)
+		}, 2},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		n, err := testCase.query(db.Find(&results)).Run()
+		if err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package tormenta_test
+
+import (
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_PartialIndexes(t *testing.T) {
+	logger := &testLogger{}
+	options := testDBOptions
+	options.Logger = logger
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", options)
+	defer db.Close()
+
+	var orders []tormenta.Record
+	for i := 0; i < 10; i++ {
+		order := &testtypes.Order{
+			Status:   "completed",
+			Customer: "bob",
+		}
+
+		if i < 4 {
+			order.Status = "open"
+		}
+
+		if i%2 == 0 {
+			order.Discount = i + 1
+		}
+
+		orders = append(orders, order)
+	}
+
+	db.Save(orders...)
+
+	// Conditional index - only the open orders are indexed
+	results := []testtypes.Order{}
+	if n, _ := db.Find(&results).Match("Customer", "bob").Run(); n != 4 {
+		t.Errorf("Testing conditional index. Expected 4 results, got %v", n)
+	}
+
+	// Completing an order takes it out of the index
+	openOrder := orders[0].(*testtypes.Order)
+	openOrder.Status = "completed"
+	db.Save(openOrder)
+
+	results = []testtypes.Order{}
+	if n, _ := db.Find(&results).Match("Customer", "bob").Run(); n != 3 {
+		t.Errorf("Testing conditional index after update. Expected 3 results, got %v", n)
+	}
+
+	// Sparse index
+	testCases := []struct {
+		testName   string
+		start, end interface{}
+		reverse    bool
+		expected   int
+		expectErr  bool
+	}{
+		{"range excluding zero", 1, 100, false, 5, false},
+		{"start only, excluding zero", 5, nil, false, 3, false},
+		{"range including zero", -1, 5, false, 0, true},
+		{"end only - includes zero", nil, 5, false, 0, true},
+		{"reversed - range excluding zero", 1, 100, true, 5, false},
+		{"reversed - start only, excluding zero", 5, nil, true, 3, false},
+		{"reversed - range including zero", -1, 5, true, 0, true},
+		{"reversed - end only - includes zero", nil, 5, true, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.Order{}
+		q := db.Find(&results)
+		if testCase.reverse {
+			q = q.Reverse()
+		}
+
+		n, err := q.Range("Discount", testCase.start, testCase.end).Run()
+		if !testCase.expectErr && err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		} else if testCase.expectErr && err == nil {
+			t.Errorf("Testing %s. Expected error but got none", testCase.testName)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+
+	results = []testtypes.Order{}
+	if _, err := db.Find(&results).Match("Discount", 0).Run(); err == nil {
+		t.Error("Testing match on zero value of sparse index. Expected an error")
+	}
+
+	// But IsZero works, as anything not in the index is zero
+	results = []testtypes.Order{}
+	if n, _ := db.Find(&results).IsZero("Discount").Run(); n != 5 {
+		t.Errorf("Testing is zero on sparse index. Expected 5 results, got %v", n)
+	}
+
+	// Whereas it can't work on a conditional index
+	results = []testtypes.Order{}
+	if _, err := db.Find(&results).IsZero("Customer").Run(); err == nil {
+		t.Error("Testing is zero on conditional index. Expected an error")
+	}
+
+	// Ordering by a partial index gets a warning
+	results = []testtypes.Order{}
+	db.Find(&results).OrderBy("Status").Run()
+	if len(logger.warnings) != 0 {
+		t.Errorf("Testing order by a full index. Expected no warnings, got %v", logger.warnings)
+	}
+
+	db.Find(&results).OrderBy("Customer").Run()
+	db.Find(&results).OrderBy("Discount").Run()
+	if len(logger.warnings) != 2 {
+		t.Errorf("Testing order by partial indexes. Expected 2 warnings, got %v", logger.warnings)
+	}
+}
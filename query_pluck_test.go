@@ -0,0 +1,224 @@
+package tormenta_test
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jpincas/gouuidv6"
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_Pluck(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	date := time.Date(2019, time.March, 1, 12, 30, 15, 0, time.UTC)
+	id := gouuidv6.New()
+	n := 5
+
+	var fullStructs []tormenta.Record
+	for i := 1; i <= 5; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:           i,
+			Int16Field:         int16(-i),
+			UintField:          uint(i),
+			FloatField:         float64(i) / 2,
+			StringField:        "Title",
+			CaseSensitiveField: "Title",
+			BoolField:          i%2 == 0,
+			DateField:          date,
+			IDField:            id,
+			DefinedIntField:    testtypes.DefinedInt(i),
+			BigIntField:        big.NewInt(int64(i) * 1000),
+			DecimalField:       testtypes.Decimal(i * 100),
+			IntSliceField:      []int{i, 10},
+			IntPtrField:        &n,
+			StructField:        testtypes.MyStruct{StructIntField: i},
+			StructSliceField:   []testtypes.MyStruct{{StructStringField: "a"}, {StructStringField: "b"}},
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	var results []testtypes.FullStruct
+	plucked, err := db.Find(&results).Range("IntField", 2, 4).OrderBy("IntField").Reverse().Pluck(
+		"IntField", "Int16Field", "UintField", "FloatField", "StringField", "CaseSensitiveField", "BoolField",
+		"DateField", "IDField", "DefinedIntField", "BigIntField", "DecimalField", "IntSliceField", "IntPtrField",
+		"StructField.StructIntField", "StructSliceField.StructStringField",
+	)
+
+	if err != nil {
+		t.Fatalf("Testing pluck. Didn't expect error [%v]", err)
+	}
+
+	if len(plucked) != 3 {
+		t.Fatalf("Testing pluck. Expected 3 results, got %v", len(plucked))
+	}
+
+	if len(results) != 0 {
+		t.Errorf("Testing pluck. Expected no records to be set on the target, got %v", len(results))
+	}
+
+	for i, result := range plucked {
+		expectedInt := 4 - i
+
+		testCases := []struct {
+			indexName string
+			expected  interface{}
+		}{
+			{tormenta.PluckIDKey, fullStructs[expectedInt-1].GetID()},
+			{"IntField", expectedInt},
+			{"Int16Field", int16(-expectedInt)},
+			{"UintField", uint(expectedInt)},
+			{"FloatField", float64(expectedInt) / 2},
+			{"StringField", "title"},
+			{"CaseSensitiveField", "Title"},
+			{"BoolField", expectedInt%2 == 0},
+			{"IDField", id},
+			{"DefinedIntField", testtypes.DefinedInt(expectedInt)},
+			{"DecimalField", testtypes.Decimal(expectedInt * 100)},
+			{"IntSliceField", []int{expectedInt, 10}},
+			{"IntPtrField", 5},
+			{"StructField.StructIntField", expectedInt},
+			{"StructSliceField.StructStringField", []string{"a", "b"}},
+		}
+
+		for _, testCase := range testCases {
+			if !reflect.DeepEqual(result[testCase.indexName], testCase.expected) {
+				t.Errorf("Testing pluck of %s. Expected %v (%T), got %v (%T)", testCase.indexName, testCase.expected, testCase.expected, result[testCase.indexName], result[testCase.indexName])
+			}
+		}
+
+		if pluckedDate, ok := result["DateField"].(time.Time); !ok || !pluckedDate.Equal(date) {
+			t.Errorf("Testing pluck of DateField. Expected %v, got %v", date, result["DateField"])
+		}
+
+		if pluckedBigInt, ok := result["BigIntField"].(*big.Int); !ok || pluckedBigInt.Int64() != int64(expectedInt)*1000 {
+			t.Errorf("Testing pluck of BigIntField. Expected %v, got %v", expectedInt*1000, result["BigIntField"])
+		}
+	}
+}
+
+func Test_Pluck_NotInIndex(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	db.Save(&testtypes.FullStruct{IntField: 1})
+
+	var results []testtypes.FullStruct
+	plucked, err := db.Find(&results).Pluck("IntField", "IntPtrField", "BigIntField")
+	if err != nil {
+		t.Fatalf("Testing pluck. Didn't expect error [%v]", err)
+	}
+
+	if len(plucked) != 1 {
+		t.Fatalf("Testing pluck. Expected 1 result, got %v", len(plucked))
+	}
+
+	if _, ok := plucked[0]["IntPtrField"]; ok {
+		t.Error("Testing pluck of nil pointer. Expected no value")
+	}
+
+	if _, ok := plucked[0]["BigIntField"]; ok {
+		t.Error("Testing pluck of nil big number. Expected no value")
+	}
+
+	if plucked[0]["IntField"] != 1 {
+		t.Errorf("Testing pluck. Expected IntField to be 1, got %v", plucked[0]["IntField"])
+	}
+}
+
+func Test_Pluck_Limit(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	var fullStructs []tormenta.Record
+	for i := 0; i < 20; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:      i,
+			StringField:   fmt.Sprintf("record %02d", 19-i),
+			IntSliceField: []int{i, i + 100},
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	var results []testtypes.FullStruct
+	plucked, err := db.Find(&results).OrderBy("IntField").Limit(3).Pluck("IntField", "StringField", "IntSliceField")
+	if err != nil {
+		t.Fatalf("Testing pluck with limit. Didn't expect error [%v]", err)
+	}
+
+	if len(plucked) != 3 {
+		t.Fatalf("Testing pluck with limit. Expected 3 results, got %v", len(plucked))
+	}
+
+	for i, result := range plucked {
+		if result["IntField"] != i || result["StringField"] != fmt.Sprintf("record %02d", 19-i) {
+			t.Errorf("Testing pluck with limit. Result %v was not as expected: %v", i, result)
+		}
+
+		if !reflect.DeepEqual(result["IntSliceField"], []int{i, i + 100}) {
+			t.Errorf("Testing pluck with limit. Expected slice values for result %v, got %v", i, result["IntSliceField"])
+		}
+	}
+}
+
+func Test_Pluck_Options(t *testing.T) {
+	options := testDBOptions
+	options.UniformNumericIndex = true
+	options.NanosecondTimeIndex = true
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", options)
+	defer db.Close()
+
+	date := time.Date(2019, time.March, 1, 12, 30, 15, 123456789, time.UTC)
+	db.Save(&testtypes.FullStruct{IntField: -7, Uint16Field: 300, DateField: date})
+
+	var results []testtypes.FullStruct
+	plucked, err := db.Find(&results).Pluck("IntField", "Uint16Field", "DateField")
+	if err != nil || len(plucked) != 1 {
+		t.Fatalf("Testing pluck with index options. Expected 1 result, got %v (error: %v)", len(plucked), err)
+	}
+
+	if plucked[0]["IntField"] != -7 {
+		t.Errorf("Testing pluck with uniform numeric index. Expected -7, got %v", plucked[0]["IntField"])
+	}
+
+	if plucked[0]["Uint16Field"] != uint16(300) {
+		t.Errorf("Testing pluck with uniform numeric index. Expected 300, got %v", plucked[0]["Uint16Field"])
+	}
+
+	if pluckedDate, ok := plucked[0]["DateField"].(time.Time); !ok || !pluckedDate.Equal(date) {
+		t.Errorf("Testing pluck with nanosecond time index. Expected %v, got %v", date, plucked[0]["DateField"])
+	}
+}
+
+func Test_Pluck_Errors(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	testCases := []struct {
+		testName   string
+		indexNames []string
+	}{
+		{"no indexes", nil},
+		{"field that doesn't exist", []string{"NoSuchField"}},
+		{"full text field", []string{"FullTextField"}},
+		{"split string field", []string{"MultipleWordField"}},
+		{"map field", []string{"IntMapField"}},
+		{"struct field", []string{"StructField"}},
+		{"no index field", []string{"NoIndexSimple"}},
+	}
+
+	for _, testCase := range testCases {
+		var results []testtypes.FullStruct
+		if _, err := db.Find(&results).Pluck(testCase.indexNames...); err == nil {
+			t.Errorf("Testing pluck of %s. Expected an error", testCase.testName)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package tormenta_test
+
+import (
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func uniformNumericOptions() tormenta.Options {
+	options := testDBOptions
+	options.UniformNumericIndex = true
+	return options
+}
+
+func Test_UniformNumericIndex_Range(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", uniformNumericOptions())
+	defer db.Close()
+
+	var fullStructs []tormenta.Record
+	for i := -10; i <= 10; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:     i * 100,
+			Int16Field:   int16(i * 100),
+			Uint8Field:   uint8(i + 10),
+			FloatField:   float64(i) * 100.5,
+			Float32Field: float32(i) / 2,
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName   string
+		indexName  string
+		start, end interface{}
+		expected   int
+	}{
+		{"int16 field, int and float bounds", "Int16Field", 1, 1000.5, 10},
+		{"int16 field, negative float bounds", "Int16Field", -250.5, -99.9, 2},
+		{"int field, int16 bounds", "IntField", int16(-1000), int16(0), 11},
+		{"uint8 field, float bounds", "Uint8Field", 2.5, 5, 3},
+		{"float field, int bounds", "FloatField", -201, 201, 5},
+		{"float32 field, float64 bounds", "Float32Field", -1.0, 1.0, 5},
+		{"float32 field, string bounds", "Float32Field", "0", "2.5", 6},
+		{"start only", "Int16Field", int64(900), nil, 2},
+		{"end only", "IntField", nil, uint(0), 11},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		n, err := db.Find(&results).Range(testCase.indexName, testCase.start, testCase.end).Run()
+		if err != nil {
+			t.Errorf("Testing %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+	}
+
+	// Exact match with a different type
+	results := []testtypes.FullStruct{}
+	if n, _ := db.Find(&results).Match("Int16Field", 300.0).Run(); n != 1 {
+		t.Errorf("Testing match with a float on an int16 field. Expected 1 result, got %v", n)
+	}
+
+	// Order - negatives first
+	results = []testtypes.FullStruct{}
+	db.Find(&results).OrderBy("Int16Field").Run()
+	if len(results) != len(fullStructs) || results[0].Int16Field != -1000 || results[len(results)-1].Int16Field != 1000 {
+		t.Errorf("Testing order by with uniform numeric index. Results were not in order")
+	}
+
+	// Sums
+	var intSum int
+	db.Find(&results).Range("IntField", 0, nil).Sum(&intSum, "IntField")
+	if intSum != 5500 {
+		t.Errorf("Testing sum of int field. Expected 5500, got %v", intSum)
+	}
+
+	var floatSum float64
+	db.Find(&results).Range("Int16Field", 0, nil).Sum(&floatSum, "FloatField")
+	if floatSum != 5527.5 {
+		t.Errorf("Testing sum of float field. Expected 5527.5, got %v", floatSum)
+	}
+}
+
+func Test_RebuildIndexes(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	var fullStructs []tormenta.Record
+	for i := 1; i <= 10; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			Int16Field:  int16(i),
+			StringField: "rebuild",
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	// Switching on the uniform format without migrating
+	// means the existing index can't be read properly
+	uniformDB := &tormenta.DB{KV: db.KV, Options: uniformNumericOptions()}
+
+	results := []testtypes.FullStruct{}
+	if n, _ := uniformDB.Find(&results).Range("Int16Field", 1, 5).Run(); n == 5 {
+		t.Error("Testing uniform numeric index before rebuild. Didn't expect the old index to work")
+	}
+
+	if err := uniformDB.RebuildIndexes(&testtypes.FullStruct{}); err != nil {
+		t.Fatalf("Testing rebuilding indexes. Got error %v", err)
+	}
+
+	results = []testtypes.FullStruct{}
+	if n, _ := uniformDB.Find(&results).Range("Int16Field", 1, 5.5).Run(); n != 5 {
+		t.Errorf("Testing uniform numeric index after rebuild. Expected 5 results, got %v", n)
+	}
+
+	// Other indexes are rebuilt too
+	results = []testtypes.FullStruct{}
+	if n, _ := uniformDB.Find(&results).Match("StringField", "rebuild").Run(); n != 10 {
+		t.Errorf("Testing string index after rebuild. Expected 10 results, got %v", n)
+	}
+}
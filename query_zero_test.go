@@ -0,0 +1,91 @@
+package tormenta_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_ZeroFilters(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	shipped := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// 10 records, with every third one having values set
+	var fullStructs []tormenta.Record
+	for i := 0; i < 10; i++ {
+		i := i
+		fullStruct := &testtypes.FullStruct{}
+
+		if i%3 == 0 {
+			fullStruct.StringField = "set"
+			fullStruct.DateField = shipped
+			fullStruct.OmitEmptyIntField = i + 1
+			fullStruct.IntPtrField = &i
+			fullStruct.IntSliceField = []int{i}
+			fullStruct.StringMapField = map[string]string{"colour": "red"}
+			fullStruct.MultipleWordField = "some words"
+		}
+
+		fullStructs = append(fullStructs, fullStruct)
+	}
+
+	db.Save(fullStructs...)
+
+	testCases := []struct {
+		testName  string
+		indexName string
+		expected  int
+	}{
+		{"string", "StringField", 6},
+		{"time", "DateField", 6},
+		{"omitempty int", "OmitEmptyIntField", 6},
+		{"pointer - nil or pointing to zero", "IntPtrField", 7},
+		{"slice", "IntSliceField", 6},
+		{"map", "StringMapField", 6},
+		{"map entry", "StringMapField.colour", 6},
+		{"split string", "MultipleWordField", 6},
+		{"float - all zero", "FloatField", 10},
+	}
+
+	for _, testCase := range testCases {
+		results := []testtypes.FullStruct{}
+		n, err := db.Find(&results).IsZero(testCase.indexName).Run()
+		if err != nil {
+			t.Errorf("Testing is zero on %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != testCase.expected {
+			t.Errorf("Testing is zero on %s. Expected %v results, got %v", testCase.testName, testCase.expected, n)
+		}
+
+		results = []testtypes.FullStruct{}
+		n, err = db.Find(&results).NotZero(testCase.indexName).Run()
+		if err != nil {
+			t.Errorf("Testing not zero on %s. Didn't expect error [%v]", testCase.testName, err)
+		}
+
+		if n != 10-testCase.expected {
+			t.Errorf("Testing not zero on %s. Expected %v results, got %v", testCase.testName, 10-testCase.expected, n)
+		}
+	}
+
+	// Combined with other filters, which uses the planner
+	results := []testtypes.FullStruct{}
+	n, err := db.Find(&results).NotZero("DateField").Range("OmitEmptyIntField", 2, 10).Run()
+	if err != nil {
+		t.Errorf("Testing not zero with a range. Didn't expect error [%v]", err)
+	}
+
+	if n != 3 {
+		t.Errorf("Testing not zero with a range. Expected 3 results, got %v", n)
+	}
+
+	// Unknown field
+	if _, err := db.Find(&results).IsZero("NoSuchField").Run(); err == nil {
+		t.Error("Testing is zero on an unknown field. Expected an error")
+	}
+}
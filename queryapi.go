@@ -48,10 +48,12 @@ func (q *Query) Match(indexName string, param interface{}) *Query {
 		return q
 	}
 
-	// If we are matching a string, lower-case it
+	// If we are matching a string, encode it as per the index,
+	// i.e. lower-case it unless the index is case sensitive
+	encoding := targetStringEncoding(q.target, indexName)
 	switch param.(type) {
 	case string:
-		param = strings.ToLower(param.(string))
+		param = encoding.normalise(param.(string))
 	}
 
 	indexKind, err := fieldKind(q.target, indexName)
@@ -61,11 +63,15 @@ func (q *Query) Match(indexName string, param interface{}) *Query {
 	}
 
 	// Create the filter and add it on
+	param = comparableInput(param)
 	q.addFilter(filter{
-		start:     param,
-		end:       param,
-		indexName: toIndexName(indexName),
-		indexKind: indexKind,
+		start:          param,
+		end:            param,
+		indexName:      toIndexName(indexName),
+		indexKind:      indexKind,
+		indexType:      fieldType(q.target, indexName),
+		stringEncoding: encoding,
+		sparse:         isSparseIndex(q.target, indexName),
 	})
 
 	return q
@@ -87,12 +93,24 @@ func (q *Query) Range(indexName string, start, end interface{}) *Query {
 		return q
 	}
 
+	// String ranges on fields with a collation use the collation index,
+	// so that the range follows the rules of the language
+	encoding := targetStringEncoding(q.target, indexName)
+	rangeIndexName := toIndexName(indexName)
+	if collation := targetCollation(q.target, indexName); collation != "" {
+		encoding.collation = collation
+		rangeIndexName = derivedIndexName(rangeIndexName, collateIndex)
+	}
+
 	// Create the filter and add it on
 	q.addFilter(filter{
-		start:     start,
-		end:       end,
-		indexName: toIndexName(indexName),
-		indexKind: indexKind,
+		start:          comparableInput(start),
+		end:            comparableInput(end),
+		indexName:      rangeIndexName,
+		indexKind:      indexKind,
+		indexType:      fieldType(q.target, indexName),
+		stringEncoding: encoding,
+		sparse:         isSparseIndex(q.target, indexName),
 	})
 
 	return q
@@ -119,6 +137,59 @@ func (q *Query) StartsWith(indexName string, s string) *Query {
 		isStartsWithQuery: true,
 		indexName:         toIndexName(indexName),
 		indexKind:         indexKind,
+		stringEncoding:    targetStringEncoding(q.target, indexName),
+	})
+
+	return q
+}
+
+// ContainsAll filters on a 'split' index for records containing every one of the given words.
+// Each argument can itself be several words, e.g. ContainsAll("description", "red wool sweater")
+func (q *Query) ContainsAll(indexName string, words ...string) *Query {
+	return q.addWordsFilter(indexName, words, false)
+}
+
+// ContainsAny filters on a 'split' index for records containing at least one of the given words
+func (q *Query) ContainsAny(indexName string, words ...string) *Query {
+	return q.addWordsFilter(indexName, words, true)
+}
+
+func (q *Query) addWordsFilter(indexName string, input []string, matchAny bool) *Query {
+	// Without a split index, words would be matched against whole field values
+	if err := requireTag(q.target, indexName, tormentaTagSplit, ErrFieldNotSplit); err != nil {
+		q.err = err
+		return q
+	}
+
+	// Apply the same splitting and encoding as the index, but
+	// skip blank words, which would otherwise match everything
+	encoding := targetStringEncoding(q.target, indexName)
+
+	var words []string
+	for _, word := range splitWords(strings.Join(input, " ")) {
+		if word != "" {
+			words = append(words, encoding.normalise(word))
+		}
+	}
+
+	if len(words) == 0 {
+		q.err = errors.New(ErrNoWordsContainsQuery)
+		return q
+	}
+
+	indexKind, err := fieldKind(q.target, indexName)
+	if err != nil {
+		q.err = err
+		return q
+	}
+
+	// Create the filter and add it on
+	q.addFilter(filter{
+		words:          uniqueStrings(words),
+		matchAnyWord:   matchAny,
+		indexName:      toIndexName(indexName),
+		indexKind:      indexKind,
+		stringEncoding: encoding,
 	})
 
 	return q
@@ -176,7 +247,8 @@ func (q *Query) UnReverse() *Query {
 	return q
 }
 
-// OrderBy specifies an index by which to order results..
+// OrderBy specifies an index by which to order results.
+// String fields tagged with a collation (e.g. `tormenta:"collate=de"`) are ordered according to the rules of the language.
 func (q *Query) OrderBy(indexName string) *Query {
 	q.orderByIndexName = toIndexName(indexName)
 	return q
@@ -18,19 +18,28 @@ const (
 	whereValueSeparator  = ":"
 	whereClauseSeparator = ","
 
-	queryStringWhere      = "where"
-	queryStringOr         = "or"
-	queryStringOrderBy    = "order"
-	queryStringOffset     = "offset"
-	queryStringLimit      = "limit"
-	queryStringReverse    = "reverse"
-	queryStringFrom       = "from"
-	queryStringTo         = "to"
-	queryStringMatch      = "match"
-	queryStringStartsWith = "startswith"
-	queryStringStart      = "start"
-	queryStringEnd        = "end"
-	queryStringIndex      = "index"
+	queryStringWhere       = "where"
+	queryStringOr          = "or"
+	queryStringOrderBy     = "order"
+	queryStringOffset      = "offset"
+	queryStringLimit       = "limit"
+	queryStringReverse     = "reverse"
+	queryStringFrom        = "from"
+	queryStringTo          = "to"
+	queryStringMatch       = "match"
+	queryStringStartsWith  = "startswith"
+	queryStringStart       = "start"
+	queryStringEnd         = "end"
+	queryStringIndex       = "index"
+	queryStringSearch      = "search"
+	queryStringContainsAll = "containsall"
+	queryStringContainsAny = "containsany"
+	queryStringFuzzy       = "fuzzy"
+	queryStringEndsWith    = "endswith"
+	queryStringContains    = "contains"
+	queryStringHasKey      = "haskey"
+	queryStringIsZero      = "iszero"
+	queryStringNotZero     = "notzero"
 
 	// Error messages
 	ErrBadFormatQueryValue            = "Bad format for query value"
@@ -43,10 +52,12 @@ const (
 	ErrBadToFormat                    = "Invalid input for TO. Expecting somthing like '2006-01-02'"
 	ErrFromIsAfterTo                  = "FROM date is after TO date, making the date range impossible"
 	ErrIndexWithNoParams              = "An index search has been specified, but index search operator has been specified"
-	ErrTooManyIndexOperatorsSpecified = "An index search can be MATCH, RANGE or STARTSWITH, but not multiple matching operators"
+	ErrTooManyIndexOperatorsSpecified = "An index search can be MATCH, RANGE, STARTSWITH, ENDSWITH, CONTAINS, CONTAINSALL, CONTAINSANY, HASKEY, ISZERO or NOTZERO, but not multiple matching operators"
 	ErrWhereClauseNoIndex             = "A WHERE clause requires an index to be specified"
 	ErrRangeTypeMismatch              = "For a range index search, START and END should be of the same type (bool, int, float, string)"
 	ErrUnmarshall                     = "Error in format of data to save: %v"
+	ErrBadSearchFormat                = "Invalid input for SEARCH. Expecting something like 'index:free text'"
+	ErrBadFuzzyFormat                 = "Invalid input for FUZZY. Expecting something like 'index:term:2'"
 )
 
 func (q *Query) Parse(ignoreLimitOffset bool, s string) error {
@@ -133,6 +144,33 @@ func (q *Query) Parse(ignoreLimitOffset bool, s string) error {
 		}
 	}
 
+	// Full text search
+	searchString := values.Get(queryStringSearch)
+	if searchString != "" {
+		searchComponents := strings.SplitN(searchString, whereValueSeparator, 2)
+		if len(searchComponents) != 2 {
+			return errors.New(ErrBadSearchFormat)
+		}
+
+		q.Search(searchComponents[0], searchComponents[1])
+	}
+
+	// Fuzzy search
+	fuzzyString := values.Get(queryStringFuzzy)
+	if fuzzyString != "" {
+		fuzzyComponents := strings.Split(fuzzyString, whereValueSeparator)
+		if len(fuzzyComponents) != 3 {
+			return errors.New(ErrBadFuzzyFormat)
+		}
+
+		maxEdits, err := strconv.Atoi(fuzzyComponents[2])
+		if err != nil {
+			return errors.New(ErrBadFuzzyFormat)
+		}
+
+		q.Fuzzy(fuzzyComponents[0], fuzzyComponents[1], maxEdits)
+	}
+
 	// And -> Or
 	orString := values.Get(queryStringOr)
 	if orString == "true" {
@@ -192,19 +230,67 @@ func (values whereClauseValues) addToQuery(q *Query, key string) error {
 	startsWithString := values.get(queryStringStartsWith)
 	startString := values.get(queryStringStart)
 	endString := values.get(queryStringEnd)
+	containsAllString := values.get(queryStringContainsAll)
+	containsAnyString := values.get(queryStringContainsAny)
+	endsWithString := values.get(queryStringEndsWith)
+	containsString := values.get(queryStringContains)
+	hasKeyString := values.get(queryStringHasKey)
+	isZeroString := values.get(queryStringIsZero)
+	notZeroString := values.get(queryStringNotZero)
+
+	// Count up the operators - START and END together make a single RANGE operator
+	noOperators := 0
+	for _, operatorString := range []string{matchString, startsWithString, startString + endString, containsAllString, containsAnyString, endsWithString, containsString, hasKeyString, isZeroString, notZeroString} {
+		if operatorString != "" {
+			noOperators++
+		}
+	}
 
-	// if no exact match or range or starsWith has been given, return an error
-	if matchString == "" && startsWithString == "" && (startString == "" && endString == "") {
+	// if no exact match or range or starsWith or contains has been given, return an error
+	if noOperators == 0 {
 		return errors.New(ErrIndexWithNoParams)
 	}
 
-	// If more than one of MATCH, RANGE and STARTSWITH have been specified
-	if matchString != "" && (startString != "" || endString != "") ||
-		matchString != "" && startsWithString != "" ||
-		startsWithString != "" && (startString != "" || endString != "") {
+	// If more than one of MATCH, RANGE, STARTSWITH and CONTAINS have been specified
+	if noOperators > 1 {
 		return errors.New(ErrTooManyIndexOperatorsSpecified)
 	}
 
+	if endsWithString != "" {
+		q.EndsWith(key, endsWithString)
+		return nil
+	}
+
+	if containsString != "" {
+		q.Contains(key, containsString)
+		return nil
+	}
+
+	if hasKeyString != "" {
+		q.HasKey(key, hasKeyString)
+		return nil
+	}
+
+	if isZeroString != "" {
+		q.IsZero(key)
+		return nil
+	}
+
+	if notZeroString != "" {
+		q.NotZero(key)
+		return nil
+	}
+
+	if containsAllString != "" {
+		q.ContainsAll(key, containsAllString)
+		return nil
+	}
+
+	if containsAnyString != "" {
+		q.ContainsAny(key, containsAnyString)
+		return nil
+	}
+
 	if matchString != "" {
 		q.Match(key, stringToInterface(matchString))
 		return nil
@@ -307,6 +393,10 @@ func (q Query) String() string {
 		components = append(components, queryComponent{queryStringOr, isOr})
 	}
 
+	if q.ranker != nil {
+		components = append(components, q.ranker.component())
+	}
+
 	var componentStrings []string
 	for _, component := range components {
 		componentStrings = append(componentStrings, fmt.Sprintf("%s=%v", component.key, component.value))
@@ -331,7 +421,23 @@ func (f filter) String() string {
 		{queryStringIndex, string(f.indexName)},
 	}
 
-	if f.start != f.end {
+	if f.isZeroQuery() {
+		operator := queryStringIsZero
+		if f.zero.not {
+			operator = queryStringNotZero
+		}
+
+		components = append(components, queryComponent{operator, true})
+	} else if f.isContainsQuery() {
+		components = append(components, queryComponent{queryStringContains, f.substring})
+	} else if f.isWordsSearch() {
+		operator := queryStringContainsAll
+		if f.matchAnyWord {
+			operator = queryStringContainsAny
+		}
+
+		components = append(components, queryComponent{operator, strings.Join(f.words, " ")})
+	} else if f.start != f.end {
 		components = append(components, queryComponent{queryStringStart, f.start}, queryComponent{queryStringEnd, f.end})
 	} else {
 		if f.isStartsWithQuery {
@@ -246,6 +246,151 @@ func Test_BuildQuery(t *testing.T) {
 			true,
 		},
 
+		// Ends with, contains
+		{
+			"ends with",
+			"where=index:ReversedField,endswith:0042",
+			db.Find(&results).EndsWith("ReversedField", "0042"),
+			true,
+			false,
+		},
+		{
+			"contains",
+			"where=index:TrigramField,contains:2019",
+			db.Find(&results).Contains("TrigramField", "2019"),
+			true,
+			false,
+		},
+		{
+			"ends with and contains",
+			"where=index:TrigramField,contains:2019,endswith:0042",
+			db.Find(&results),
+			true,
+			true,
+		},
+
+		// Map keys
+		{
+			"has key",
+			"where=index:StringMapField,haskey:Colour",
+			db.Find(&results).HasKey("StringMapField", "Colour"),
+			true,
+			false,
+		},
+		{
+			"has key and match",
+			"where=index:StringMapField,haskey:Colour,match:red",
+			db.Find(&results),
+			true,
+			true,
+		},
+		{
+			"match on map entry",
+			"where=index:StringMapField.colour,match:red",
+			db.Find(&results).Match("StringMapField.colour", "red"),
+			true,
+			false,
+		},
+
+		// Zero values
+		{
+			"is zero",
+			"where=index:DateField,iszero:true",
+			db.Find(&results).IsZero("DateField"),
+			true,
+			false,
+		},
+		{
+			"not zero",
+			"where=index:DateField,notzero:true",
+			db.Find(&results).NotZero("DateField"),
+			true,
+			false,
+		},
+		{
+			"is zero vs not zero",
+			"where=index:DateField,iszero:true",
+			db.Find(&results).NotZero("DateField"),
+			false,
+			false,
+		},
+
+		// Contains
+		{
+			"contains all",
+			"where=index:MultipleWordField,containsall:red wool",
+			db.Find(&results).ContainsAll("MultipleWordField", "red", "wool"),
+			true,
+			false,
+		},
+		{
+			"contains any",
+			"where=index:MultipleWordField,containsany:red wool",
+			db.Find(&results).ContainsAny("MultipleWordField", "red wool"),
+			true,
+			false,
+		},
+		{
+			"contains all vs any",
+			"where=index:MultipleWordField,containsall:red wool",
+			db.Find(&results).ContainsAny("MultipleWordField", "red wool"),
+			false,
+			false,
+		},
+		{
+			"contains and match",
+			"where=index:MultipleWordField,containsall:red wool,match:red",
+			db.Find(&results),
+			true,
+			true,
+		},
+
+		// Full text search
+		{
+			"search",
+			"search=FullTextField:red wool sweaters",
+			db.Find(&results).Search("FullTextField", "red wool sweaters"),
+			true,
+			false,
+		},
+		{
+			"search - different terms",
+			"search=FullTextField:blue wool sweaters",
+			db.Find(&results).Search("FullTextField", "red wool sweaters"),
+			false,
+			false,
+		},
+		{
+			"search - no index",
+			"search=red wool sweaters",
+			db.Find(&results),
+			true,
+			true,
+		},
+
+		// Fuzzy
+		{
+			"fuzzy",
+			"fuzzy=StringField:smith:2",
+			db.Find(&results).Fuzzy("StringField", "smith", 2),
+			true,
+			false,
+		},
+		{
+			"fuzzy - different edits",
+			"fuzzy=StringField:smith:1",
+			db.Find(&results).Fuzzy("StringField", "smith", 2),
+			false,
+			false,
+		},
+		{
+			"fuzzy - no edits",
+			"fuzzy=StringField:smith",
+			db.Find(&results),
+			true,
+			true,
+		},
+
 		// Stack 'em up!
 		{
 			"limit, offset",
@@ -7,6 +7,10 @@ func quickSum(target interface{}, item *badger.Item) {
 	// the sum target given that we don't know what type it is
 	switch target.(type) {
 
+	// Custom types
+	case IndexValueDecoder:
+		quickSumDecoded(target.(IndexValueDecoder), item.Key())
+
 	// Signed Ints
 	case *int:
 		// Reminder - decoding the index values only works for fixed length integers
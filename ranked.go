@@ -0,0 +1,98 @@
+package tormenta
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+const ErrMultipleRanked = "Only one ranked search can be applied to a query"
+
+// ranker is a search that scores records rather than just including or excluding them,
+// e.g. full text search or fuzzy matching.
+// A query can have at most one, and it decides the order of the results
+// unless the query is explicitly ordered by an index.
+type ranker interface {
+	// rank returns the matching IDs, best match first
+	rank(txn *badger.Txn, keyRoot []byte, from, to gouuidv6.UUID, step *PlanStep) (idList, error)
+
+	// describe provides the starting point for the search's plan step
+	describe() PlanStep
+
+	// component is used to represent the search in the query string
+	component() queryComponent
+}
+
+func (q *Query) setRanker(r ranker) *Query {
+	if q.ranker != nil {
+		q.err = errors.New(ErrMultipleRanked)
+		return q
+	}
+
+	q.ranker = r
+	return q
+}
+
+// rankedIDs orders IDs by descending score, falling back to ID order for ties
+func rankedIDs(scores map[gouuidv6.UUID]float64) (ids idList) {
+	for id := range scores {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+
+		return ids[i].Compare(ids[j])
+	})
+
+	return
+}
+
+func (q *Query) rankedIDs(txn *badger.Txn) (idList, error) {
+	t := time.Now()
+	step := q.profile.addStep(q.ranker.describe())
+
+	ids, err := q.ranker.rank(txn, q.keyRoot, q.from, q.to, step)
+	if err != nil {
+		return idList{}, err
+	}
+
+	step.finish(t, len(ids))
+
+	// Combine with the filters according to the AND/OR logic,
+	// keeping the ranked results at the top
+	if len(q.filters) > 0 {
+		filtered, err := q.filterIDs(txn)
+		if err != nil {
+			return idList{}, err
+		}
+
+		combined := map[gouuidv6.UUID]bool{}
+		for _, id := range q.idsCombinator(ids, filtered) {
+			combined[id] = true
+		}
+
+		var results idList
+		for _, id := range append(ids, filtered...) {
+			if combined[id] {
+				results = append(results, id)
+				delete(combined, id)
+			}
+		}
+
+		ids = results
+	}
+
+	// Ordering by an index overrides the ranking,
+	// and the index search will take care of limit and offset
+	if len(q.orderByIndexName) == 0 {
+		ids = ids.limitOffset(q.limit, q.offset)
+	}
+
+	return ids, nil
+}
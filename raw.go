@@ -0,0 +1,172 @@
+package tormenta
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/buger/jsonparser"
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Raw JSON
+// Records are stored as JSON (with the default serialiser), so when they are
+// going straight back out as JSON, e.g. from an HTTP API, there is no need to unmarshal
+// and then re-marshal them.  GetRaw and RunRaw return the stored bytes as they are,
+// optionally projected down to a few fields with Select.
+// As records are never unmarshalled, PostGet isn't run - records that need to
+// process their output can implement RawPostGetter instead.
+
+// RawPostGetter is the raw equivalent of PostGet, run on the stored JSON of a record
+// by GetRaw and RunRaw, before any projection.
+type RawPostGetter interface {
+	PostGetRaw(raw json.RawMessage, ctx map[string]interface{}) (json.RawMessage, error)
+}
+
+// GetRaw retrieves the stored JSON of an entity, either according to the ID set on the entity,
+// or using a separately specified ID (optional, takes priority).
+// The entity itself is not modified.
+func (db DB) GetRaw(entity Record, ids ...gouuidv6.UUID) (json.RawMessage, bool, error) {
+	t := time.Now()
+
+	id := entity.GetID()
+	if len(ids) > 0 {
+		id = ids[0]
+	}
+
+	txn := db.KV.NewTransaction(false)
+	defer txn.Discard()
+
+	raw, found, err := db.getRaw(txn, entity, noCTX, id)
+
+	var n int
+	if found {
+		n = 1
+	}
+
+	db.observe(OpGet, KeyRoot(entity), t, n, err)
+	db.debugLogGet(entity, t, n, err, id)
+	return raw, found, err
+}
+
+func (db DB) getRaw(txn *badger.Txn, entity Record, ctx map[string]interface{}, id gouuidv6.UUID) (json.RawMessage, bool, error) {
+	item, err := txn.Get(newContentKey(KeyRoot(entity), id).bytes())
+	if err == badger.ErrKeyNotFound {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	raw, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if postGetter, ok := entity.(RawPostGetter); ok {
+		raw, err = postGetter.PostGetRaw(raw, ctx)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return raw, true, nil
+}
+
+// Select restricts the JSON returned by RunRaw to the given fields.
+// Fields are specified by their keys in the stored JSON, with nested fields separated by dots,
+// e.g. Select("ID", "Name", "Address.City").  Note that records with fields tagged `tormenta:"-"`
+// are stored with Go field names as keys, regardless of JSON tags.  Fields missing from a record are left out.
+// Select has no effect on Run.
+func (q *Query) Select(fields ...string) *Query {
+	for _, field := range fields {
+		q.selectFields = append(q.selectFields, strings.Split(field, indexKeySeparator))
+	}
+
+	return q
+}
+
+// RunRaw executes the query, returning the stored JSON of the matching records
+// (projected according to Select), rather than setting them on the target
+func (q *Query) RunRaw() ([]json.RawMessage, error) {
+	t := time.Now()
+
+	results, err := q.runRaw()
+	q.report(t, len(results), err)
+	return results, err
+}
+
+func (q *Query) runRaw() ([]json.RawMessage, error) {
+	txn := q.db.KV.NewTransaction(false)
+	defer txn.Discard()
+
+	ids, err := q.resultIDs(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchStart := time.Now()
+	fetchStep := q.profile.addStep(PlanStep{Stage: PlanStageFetch})
+
+	// As with Run, 'First' type queries only return the first record
+	var entity Record
+	if q.single {
+		entity = newRecord(q.target)
+		if len(ids) > 1 {
+			ids = ids[:1]
+		}
+	} else {
+		entity = newRecordFromSlice(q.target)
+	}
+
+	results := []json.RawMessage{}
+	for _, id := range ids {
+		raw, found, err := q.db.getRaw(txn, entity, q.ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		// As with Run, records which can't be found are skipped
+		if !found {
+			continue
+		}
+
+		if len(q.selectFields) > 0 {
+			raw, err = projectJSON(raw, q.selectFields)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		results = append(results, raw)
+	}
+
+	fetchStep.fetched(fetchStart, len(results))
+	return results, nil
+}
+
+// projectJSON builds a new JSON object from just the given paths of a JSON object
+func projectJSON(raw []byte, paths [][]string) (json.RawMessage, error) {
+	projected := []byte("{}")
+
+	for _, path := range paths {
+		value, dataType, _, err := jsonparser.Get(raw, path...)
+		if err == jsonparser.KeyPathNotFoundError {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		// Strings come back without their quotes
+		if dataType == jsonparser.String {
+			value = append(append([]byte(`"`), value...), '"')
+		}
+
+		projected, err = jsonparser.Set(projected, value, path...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return projected, nil
+}
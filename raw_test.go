@@ -0,0 +1,166 @@
+package tormenta_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jpincas/gouuidv6"
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_GetRaw(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	fullStruct := testtypes.FullStruct{IntField: 1, StringField: "Test"}
+	db.Save(&fullStruct)
+
+	raw, found, err := db.GetRaw(&testtypes.FullStruct{}, fullStruct.ID)
+	if err != nil || !found {
+		t.Fatalf("Testing get raw. Expected to find record, got found %v and error %v", found, err)
+	}
+
+	var result testtypes.FullStruct
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("Testing get raw. Couldn't unmarshal result: %v", err)
+	}
+
+	if result.ID != fullStruct.ID || result.IntField != 1 || result.StringField != "Test" {
+		t.Errorf("Testing get raw. Result doesn't match saved record: %s", raw)
+	}
+
+	// The raw post get hook is run instead of PostGet
+	if !result.Retrieved {
+		t.Error("Testing get raw. Expected raw post get hook to have run")
+	}
+
+	// ID from the entity
+	if _, found, _ := db.GetRaw(&fullStruct); !found {
+		t.Error("Testing get raw with ID from entity. Expected to find record")
+	}
+
+	// Not found
+	raw, found, err = db.GetRaw(&testtypes.FullStruct{}, gouuidv6.New())
+	if err != nil || found || raw != nil {
+		t.Errorf("Testing get raw of non-existent record. Expected nothing, got %s (found %v, error %v)", raw, found, err)
+	}
+}
+
+func Test_RunRaw(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	var fullStructs []tormenta.Record
+	for i := 1; i <= 5; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:    i,
+			StringField: "Test",
+			StructField: testtypes.MyStruct{StructIntField: i, StructStringField: "nested"},
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	var results []testtypes.FullStruct
+	raws, err := db.Find(&results).Range("IntField", 2, 4).OrderBy("IntField").Reverse().SetContext("sessionid", "session").RunRaw()
+	if err != nil {
+		t.Fatalf("Testing run raw. Didn't expect error [%v]", err)
+	}
+
+	if len(raws) != 3 {
+		t.Fatalf("Testing run raw. Expected 3 results, got %v", len(raws))
+	}
+
+	if len(results) != 0 {
+		t.Errorf("Testing run raw. Expected no records to be set on the target, got %v", len(results))
+	}
+
+	for i, raw := range raws {
+		var result testtypes.FullStruct
+		json.Unmarshal(raw, &result)
+
+		if result.IntField != 4-i {
+			t.Errorf("Testing run raw. Expected result %v to have IntField %v, got %v", i, 4-i, result.IntField)
+		}
+
+		if !result.Retrieved || result.TriggerString != "session" {
+			t.Errorf("Testing run raw. Expected raw post get hook to have run with context")
+		}
+	}
+
+	// First
+	var fullStruct testtypes.FullStruct
+	raws, err = db.First(&fullStruct).Match("IntField", 5).RunRaw()
+	if err != nil || len(raws) != 1 {
+		t.Errorf("Testing run raw with first. Expected 1 result, got %v (error %v)", len(raws), err)
+	}
+
+	// First, with multiple filters going through the planner
+	raws, err = db.First(&fullStruct).Match("StringField", "Test").Range("IntField", 2, 4).RunRaw()
+	if err != nil || len(raws) != 1 {
+		t.Errorf("Testing run raw with first and multiple filters. Expected 1 result, got %v (error %v)", len(raws), err)
+	}
+}
+
+func Test_RunRaw_Select(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	fullStruct := testtypes.FullStruct{
+		IntField:         1,
+		StringField:      "Test \"quoted\"",
+		BoolField:        true,
+		IntSliceField:    []int{1, 2},
+		StructField:      testtypes.MyStruct{StructIntField: 2, StructStringField: "nested"},
+		StructSliceField: []testtypes.MyStruct{{StructIntField: 3}},
+	}
+
+	db.Save(&fullStruct)
+
+	var results []testtypes.FullStruct
+	raws, err := db.Find(&results).Select(
+		"ID", "IntField", "StringField", "BoolField", "IntSliceField",
+		"StructField.StructStringField", "StructSliceField", "NoSuchField",
+	).RunRaw()
+
+	if err != nil || len(raws) != 1 {
+		t.Fatalf("Testing run raw with select. Expected 1 result, got %v (error %v)", len(raws), err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(raws[0], &result); err != nil {
+		t.Fatalf("Testing run raw with select. Couldn't unmarshal %s: %v", raws[0], err)
+	}
+
+	expected := map[string]interface{}{
+		"IntField":      float64(1),
+		"StringField":   "Test \"quoted\"",
+		"BoolField":     true,
+		"IntSliceField": []interface{}{float64(1), float64(2)},
+		"StructField": map[string]interface{}{
+			"StructStringField": "nested",
+		},
+		"StructSliceField": []interface{}{
+			map[string]interface{}{
+				"StructIntField":    float64(3),
+				"StructStringField": "",
+				"StructFloatField":  float64(0),
+				"StructBoolField":   false,
+				"StructDateField":   "0001-01-01T00:00:00Z",
+			},
+		},
+	}
+
+	var id struct{ ID gouuidv6.UUID }
+	json.Unmarshal(raws[0], &id)
+	if id.ID != fullStruct.ID {
+		t.Errorf("Testing run raw with select. Expected ID %v, got %v", fullStruct.ID, id.ID)
+	}
+
+	delete(result, "ID")
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Testing run raw with select.\nExpected %v\nGot      %v", expected, result)
+	}
+}
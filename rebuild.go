@@ -0,0 +1,85 @@
+package tormenta
+
+import (
+	"bytes"
+
+	"github.com/dgraph-io/badger"
+)
+
+// Number of records to reindex per transaction when rebuilding
+const rebuildBatchSize = 1000
+
+// RebuildIndexes drops and rebuilds all the indexes for the given entity types,
+// e.g. db.RebuildIndexes(&Product{}, &Order{}).
+// Use it to migrate existing data after changing index options (such as UniformNumericIndex)
+// or index tags on a struct.  Records are not resaved, so no triggers are run.
+//
+// It must be run offline, with nothing else using the DB: the old indexes are dropped first
+// and the new ones written in batches afterwards, so until it finishes, queries
+// will be missing results, and if it fails partway, the indexes are left partly
+// built until it is run again.
+func (db DB) RebuildIndexes(entities ...Record) error {
+	for _, entity := range entities {
+		if err := db.rebuildIndexes(entity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (db DB) rebuildIndexes(entity Record) error {
+	if err := checkNotView(entity); err != nil {
+		return err
+	}
+
+	root := KeyRoot(entity)
+
+	indexPrefix := bytes.Join([][]byte{[]byte(indexKeyPrefix), root, {}}, []byte(keySeparator))
+	if err := db.KV.DropPrefix(indexPrefix); err != nil {
+		return err
+	}
+
+	var batch []Record
+	flush := func() error {
+		err := db.KV.Update(func(txn *badger.Txn) error {
+			for _, record := range batch {
+				if err := db.index(txn, record); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+		batch = nil
+		return err
+	}
+
+	contentPrefix := append(newContentKey(root).bytes(), []byte(keySeparator)...)
+	err := db.KV.View(func(txn *badger.Txn) error {
+		return iteratePrefix(txn, contentPrefix, true, func(item *badger.Item) error {
+			record := newRecord(entity)
+			if err := item.Value(func(val []byte) error {
+				return db.unserialise(val, record)
+			}); err != nil {
+				return err
+			}
+
+			record.SetID(extractID(item.Key()))
+			batch = append(batch, record)
+
+			if len(batch) >= rebuildBatchSize {
+				return flush()
+			}
+
+			return nil
+		})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
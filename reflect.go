@@ -3,6 +3,7 @@ package tormenta
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 var (
@@ -22,6 +23,12 @@ func indexStringForThisEntity(record Record) string {
 
 func entityTypeAndValue(t interface{}) ([]byte, reflect.Value) {
 	e := reflect.Indirect(reflect.ValueOf(t))
+
+	// Views read the records of the entity they are a view of
+	if entity, ok := viewEntity(e.Type()); ok {
+		return typeToKeyRoot(entity), e
+	}
+
 	return typeToKeyRoot(e.Type().String()), e
 }
 
@@ -58,25 +65,84 @@ func fieldValue(entity Record, fieldName string) reflect.Value {
 }
 
 func fieldKind(target interface{}, fieldName string) (reflect.Kind, error) {
-	// The target will either be a pointer to slice or struct
-	// Start of assuming its a pointer to a struct
-	ss := reflect.ValueOf(target).Elem()
-
-	// Check if its a slice, and if so,
-	// get the underlying type, create a new struct value pointer,
-	// and dereference it
-	if ss.Type().Kind() == reflect.Slice {
-		ss = reflect.New(ss.Type().Elem()).Elem()
+	field, err := structField(target, fieldName)
+	if err != nil {
+		return 0, err
+	}
+
+	return field.Type.Kind(), nil
+}
+
+// structField looks up a field on the target,
+// which will either be a pointer to a slice or a struct.
+// Index names of nested struct fields and map entries, e.g. 'Nested.Field' or 'Map.key',
+// are followed down to the nested field or the map's value type,
+// which keeps the tags of the map field.
+// Pointer fields are indexed as the values they point to, so the type is dereferenced.
+func structField(target interface{}, fieldName string) (reflect.StructField, error) {
+	t := reflect.TypeOf(target).Elem()
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	// Computed indexes aren't fields, but are looked up in the same way
+	recordType := t
+	notFound := func() (reflect.StructField, error) {
+		if computed, ok := computedField(recordType, fieldName); ok {
+			return computed, nil
+		}
+
+		return reflect.StructField{}, fmt.Errorf(ErrFieldCouldNotBeFound, fieldName)
+	}
+
+	var field reflect.StructField
+	for i, name := range strings.Split(fieldName, indexKeySeparator) {
+		if i > 0 {
+			switch t.Kind() {
+			case reflect.Map:
+				field.Type = derefType(t.Elem())
+				t = field.Type
+				continue
+
+			// Nested slices of structs
+			case reflect.Slice, reflect.Array:
+				t = derefType(t.Elem())
+			}
+		}
+
+		if t.Kind() != reflect.Struct {
+			return notFound()
+		}
+
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return notFound()
+		}
+
+		field = f
+		field.Type = derefType(f.Type)
+		t = field.Type
 	}
 
-	// At this point, independently of whether the input was a struct or slice,
-	// we can get the required field by name and get its kind
-	v := ss.FieldByName(fieldName)
-	if !v.IsValid() {
-		return 0, fmt.Errorf(ErrFieldCouldNotBeFound, fieldName)
+	return field, nil
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// fieldType returns the type of a field on the target, or nil if it can't be found
+func fieldType(target interface{}, fieldName string) reflect.Type {
+	field, err := structField(target, fieldName)
+	if err != nil {
+		return nil
 	}
 
-	return v.Type().Kind(), nil
+	return field.Type
 }
 
 // newSlice sets up a new target slice for results
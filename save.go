@@ -13,10 +13,21 @@ const (
 )
 
 func (db DB) Save(entities ...Record) (int, error) {
+	return db.SaveWithContext(noCTX, entities...)
+}
+
+// SaveWithContext saves entities in the same way as Save,
+// with a context giving e.g. the actor for the audit trail
+func (db DB) SaveWithContext(ctx map[string]interface{}, entities ...Record) (int, error) {
+	t := time.Now()
 
+	var auditEntries []*AuditEntry
 	err := db.KV.Update(func(txn *badger.Txn) error {
 		for i := 0; i < len(entities); i++ {
 			entity := entities[i]
+			if err := checkNotView(entity); err != nil {
+				return err
+			}
 
 			// Make a copy of the entity and attempt to get the old
 			// version from the DB for deindexing
@@ -29,7 +40,7 @@ func (db DB) Save(entities ...Record) (int, error) {
 			// If it does exist, then we'll need to deindex it.
 			// If it's a new entity then deindexing is not necessary
 			if found {
-				if err := deIndex(txn, newEntity); err != nil {
+				if err := db.deIndex(txn, newEntity); err != nil {
 					return err
 				}
 			}
@@ -67,22 +78,58 @@ func (db DB) Save(entities ...Record) (int, error) {
 
 			// Before serialisation, we turn the entity
 			// into a map, with nosave fields removed
-			data, err := db.serialise(removeSkippedFields(e))
+			fields := removeSkippedFields(e)
+			data, err := db.serialise(fields)
 
 			if err != nil {
 				return err
 			}
 
+			// Mark the write as a create or an update for subscribers,
+			// and keep the previous version for them if there is one
+			userMeta := userMetaCreated
+			if found {
+				userMeta = userMetaUpdated
+				if err := db.savePrevious(txn, keyRoot, model.ID); err != nil {
+					return err
+				}
+
+				if err := db.saveHistory(txn, entity, keyRoot, model.ID); err != nil {
+					return err
+				}
+			}
+
 			key := newContentKey(keyRoot, model.ID).bytes()
-			if err := txn.Set(key, data); err != nil {
+			if err := txn.SetEntry(badger.NewEntry(key, data).WithMeta(userMeta)); err != nil {
 				return err
 			}
 
+			// Audit the change against the old version
+			if db.auditing() {
+				action, oldFields := EventCreated, map[string]interface{}(nil)
+				if found {
+					action, oldFields = EventUpdated, removeSkippedFields(recordValue(newEntity))
+				}
+
+				auditEntry, err := db.auditEntry(ctx, action, keyRoot, model.ID, oldFields, fields)
+				if err != nil {
+					return err
+				}
+
+				if err := db.saveAuditEntry(txn, auditEntry); err != nil {
+					return err
+				}
+
+				if auditEntry != nil {
+					auditEntries = append(auditEntries, auditEntry)
+				}
+			}
+
 			// Post save trigger
 			entity.PostSave()
 
 			// indexing
-			if err := index(txn, entity); err != nil {
+			if err := db.index(txn, entity); err != nil {
 				return err
 			}
 
@@ -92,12 +139,25 @@ func (db DB) Save(entities ...Record) (int, error) {
 	})
 
 	if err != nil {
+		db.observe(OpSave, saveEntityType(entities), t, 0, err)
 		return 0, err
 	}
 
+	db.reportAudit(auditEntries)
+	db.observe(OpSave, saveEntityType(entities), t, len(entities), nil)
 	return len(entities), nil
 }
 
+// saveEntityType is the entity type reported to metrics for a save.
+// A save can contain entities of different types, so we go with the first one.
+func saveEntityType(entities []Record) []byte {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	return KeyRoot(entities[0])
+}
+
 // The regular 'Save' function is atomic - if there is any error, the whole thing
 // gets rolled back.  If you don't care about atomicity, you can use SaveIndividually
 
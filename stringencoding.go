@@ -0,0 +1,79 @@
+package tormenta
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// String encoding options.
+// By default, strings are lower cased when they are indexed, and so is user input when
+// querying, making all string searches case insensitive.
+// - `tormenta:"casesensitive"` indexes strings exactly as they are, e.g. for product SKUs
+// - `tormenta:"fold"` decomposes strings (Unicode NFKD) and removes the diacritics,
+// so that "José" and "Jose" index and match the same
+// The same encoding is applied to the main index and all the supplementary indexes of the field
+// (split, trigram, reversed), and to query input on that field.
+
+type stringEncoding struct {
+	caseSensitive bool
+	fold          bool
+
+	// Language to encode strings as collation sort keys in,
+	// only set when querying the collation index of a field (see collate.go)
+	collation string
+}
+
+func fieldStringEncoding(field reflect.StructField) stringEncoding {
+	return stringEncoding{
+		caseSensitive: isTaggedWith(field, tormentaTagCaseSensitive),
+		fold:          isTaggedWith(field, tormentaTagFold),
+	}
+}
+
+// targetStringEncoding looks up the string encoding for a field on the query target.
+// If the field can't be found, the default encoding is used.
+func targetStringEncoding(target interface{}, indexName string) stringEncoding {
+	field, err := structField(target, indexName)
+	if err != nil {
+		return stringEncoding{}
+	}
+
+	return fieldStringEncoding(field)
+}
+
+func (e stringEncoding) normalise(s string) string {
+	if e.fold {
+		s = foldString(s)
+	}
+
+	if !e.caseSensitive {
+		s = strings.ToLower(s)
+	}
+
+	return s
+}
+
+// encode returns the normalised string as index content
+func (e stringEncoding) encode(s string) []byte {
+	if e.collation != "" {
+		return collationKey(e.collation, e.normalise(s))
+	}
+
+	return []byte(e.normalise(s))
+}
+
+var diacriticFolder = transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)))
+
+func foldString(s string) string {
+	result, _, err := transform.String(diacriticFolder, s)
+	if err != nil {
+		return s
+	}
+
+	return result
+}
@@ -0,0 +1,230 @@
+package tormenta
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/dgraph-io/badger/pb"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Change data capture
+// Subscribe is built on Badger's subscriptions to the content keys of the given entity types,
+// so it sees every write from Save (including multiple and cascading saves) and Delete.
+// Badger only publishes the new value of a key, so:
+// - saves mark content keys as created or updated in the key's user meta
+// - while there are subscriptions, saves and deletes also write the previous value
+// of a record to a short lived key alongside the content key:
+// p:root:id -> previous value
+// which is published in the same batch, giving the old record.
+// Records written while a subscription was starting, or through a different DB connection,
+// may be missing their old record.
+
+// Event types
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+	EventDeleted = "deleted"
+
+	ErrNoSubscriptionEntities = "At least one entity type is required to subscribe to"
+)
+
+// User meta on content keys
+const (
+	userMetaCreated byte = 1 << iota
+	userMetaUpdated
+)
+
+// How long previous values are kept for
+const previousValueTTL = time.Minute
+
+// Event is a change to a record.
+// Old is nil for created records, and New is nil for deleted records.
+type Event struct {
+	Type       string
+	EntityType string
+	ID         gouuidv6.UUID
+	Old, New   Record
+	Timestamp  time.Time
+}
+
+// Subscribe calls fn with an event for each record of the given entity types
+// that is created, updated or deleted, in the order they are written,
+// e.g. db.Subscribe(ctx, notify, &Product{}, &Order{}).
+// It blocks until the context is cancelled (when it returns nil) or an error occurs,
+// so should normally be run in a goroutine.
+func (db DB) Subscribe(ctx context.Context, fn func(Event), entities ...Record) error {
+	if len(entities) == 0 {
+		return errors.New(ErrNoSubscriptionEntities)
+	}
+
+	var prefixes [][]byte
+	prototypes := map[string]Record{}
+	for _, entity := range entities {
+		root := KeyRoot(entity)
+		prototypes[string(root)] = entity
+		prefixes = append(prefixes, contentPrefix(root), previousPrefix(root))
+	}
+
+	if db.subscriptions != nil {
+		atomic.AddInt32(db.subscriptions, 1)
+		defer atomic.AddInt32(db.subscriptions, -1)
+	}
+
+	err := db.KV.Subscribe(ctx, func(kvs *badger.KVList) error {
+		events, err := db.changeEvents(kvs, prototypes)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			fn(event)
+		}
+
+		return nil
+	}, prefixes...)
+
+	if err == ctx.Err() {
+		return nil
+	}
+
+	return err
+}
+
+// changeEvents turns a batch of changed keys into events
+func (db DB) changeEvents(kvs *badger.KVList, prototypes map[string]Record) (events []Event, err error) {
+	// Previous values are matched to content keys written in the same transaction,
+	// which are published together, but in no particular order
+	type change struct {
+		id      gouuidv6.UUID
+		version uint64
+	}
+
+	previous := map[change][]byte{}
+	for _, kv := range kvs.Kv {
+		if bytes.HasPrefix(kv.Key, []byte(previousKeyPrefix)) {
+			previous[change{extractID(kv.Key), kv.Version}] = kv.Value
+		}
+	}
+
+	for _, kv := range kvs.Kv {
+		if !bytes.HasPrefix(kv.Key, []byte(contentKeyPrefix)) {
+			continue
+		}
+
+		root := keyEntityType(kv.Key)
+		prototype, ok := prototypes[string(root)]
+		if !ok {
+			continue
+		}
+
+		event := Event{
+			EntityType: string(root),
+			ID:         extractID(kv.Key),
+			Timestamp:  time.Now(),
+		}
+
+		oldValue, hasOldValue := previous[change{event.ID, kv.Version}]
+
+		switch {
+		case len(kv.Value) == 0:
+			event.Type = EventDeleted
+		case changeUserMeta(kv)&userMetaCreated != 0:
+			event.Type = EventCreated
+		case changeUserMeta(kv)&userMetaUpdated != 0, hasOldValue:
+			event.Type = EventUpdated
+		default:
+			event.Type = EventCreated
+		}
+
+		if hasOldValue {
+			if event.Old, err = db.changedRecord(prototype, event.ID, oldValue); err != nil {
+				return nil, err
+			}
+		}
+
+		if event.Type != EventDeleted {
+			if event.New, err = db.changedRecord(prototype, event.ID, kv.Value); err != nil {
+				return nil, err
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	return
+}
+
+func (db DB) changedRecord(prototype Record, id gouuidv6.UUID, value []byte) (Record, error) {
+	record := newRecord(prototype)
+	if err := db.unserialise(value, record); err != nil {
+		return nil, err
+	}
+
+	record.SetID(id)
+	record.PostGet(noCTX)
+	return record, nil
+}
+
+// changeUserMeta returns the user meta of a changed key.
+// This version of Badger publishes it as the meta.
+func changeUserMeta(kv *pb.KV) byte {
+	for _, meta := range [][]byte{kv.UserMeta, kv.Meta} {
+		if len(meta) > 0 && meta[0] != 0 {
+			return meta[0]
+		}
+	}
+
+	return 0
+}
+
+func keyEntityType(key []byte) []byte {
+	s := bytes.Split(key, []byte(keySeparator))
+	if len(s) < 2 {
+		return nil
+	}
+
+	return s[1]
+}
+
+func contentPrefix(root []byte) []byte {
+	return append(newContentKey(root).bytes(), []byte(keySeparator)...)
+}
+
+func previousPrefix(root []byte) []byte {
+	return bytes.Join([][]byte{[]byte(previousKeyPrefix), root, {}}, []byte(keySeparator))
+}
+
+func previousKey(root []byte, id gouuidv6.UUID) []byte {
+	return bytes.Join([][]byte{[]byte(previousKeyPrefix), root, id.Bytes()}, []byte(keySeparator))
+}
+
+func (db DB) hasSubscriptions() bool {
+	return db.subscriptions != nil && atomic.LoadInt32(db.subscriptions) > 0
+}
+
+// savePrevious keeps the current value of a record for subscribers,
+// before it is overwritten or deleted in the same transaction
+func (db DB) savePrevious(txn *badger.Txn, root []byte, id gouuidv6.UUID) error {
+	if !db.hasSubscriptions() {
+		return nil
+	}
+
+	item, err := txn.Get(newContentKey(root, id).bytes())
+	if err == badger.ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return err
+	}
+
+	return txn.SetEntry(badger.NewEntry(previousKey(root, id), value).WithTTL(previousValueTTL))
+}
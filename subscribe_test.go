@@ -0,0 +1,126 @@
+package tormenta_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_Subscribe(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan tormenta.Event, 100)
+	done := make(chan error)
+
+	go func() {
+		done <- db.Subscribe(ctx, func(e tormenta.Event) { events <- e }, &testtypes.FullStruct{})
+	}()
+
+	// Give the subscription time to start
+	time.Sleep(100 * time.Millisecond)
+
+	fullStruct := testtypes.FullStruct{IntField: 1}
+	db.Save(&fullStruct)
+
+	fullStruct.IntField = 2
+	db.Save(&fullStruct)
+
+	// Other entity types are not included
+	db.Save(&testtypes.RelatedStruct{})
+
+	another := testtypes.FullStruct{IntField: 3}
+	yetAnother := testtypes.FullStruct{IntField: 4}
+	db.Save(&another, &yetAnother)
+
+	db.Delete(&testtypes.FullStruct{}, fullStruct.ID)
+
+	expected := []struct {
+		eventType      string
+		id             interface{}
+		oldInt, newInt int
+	}{
+		{tormenta.EventCreated, fullStruct.ID, 0, 1},
+		{tormenta.EventUpdated, fullStruct.ID, 1, 2},
+		{tormenta.EventCreated, nil, 0, 0},
+		{tormenta.EventCreated, nil, 0, 0},
+		{tormenta.EventDeleted, fullStruct.ID, 2, 0},
+	}
+
+	var received []tormenta.Event
+	for range expected {
+		select {
+		case e := <-events:
+			received = append(received, e)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Testing subscribe. Timed out waiting for events, got %v", received)
+		}
+	}
+
+	for i, e := range expected {
+		event := received[i]
+
+		if event.Type != e.eventType {
+			t.Errorf("Testing subscribe event %v. Expected type %s, got %s", i, e.eventType, event.Type)
+		}
+
+		if event.EntityType != "fullstruct" {
+			t.Errorf("Testing subscribe event %v. Expected entity type fullstruct, got %s", i, event.EntityType)
+		}
+
+		if e.id != nil && event.ID != e.id {
+			t.Errorf("Testing subscribe event %v. Expected ID %v, got %v", i, e.id, event.ID)
+		}
+
+		if event.Timestamp.IsZero() {
+			t.Errorf("Testing subscribe event %v. Expected a timestamp", i)
+		}
+
+		if e.oldInt == 0 && event.Old != nil {
+			t.Errorf("Testing subscribe event %v. Expected no old record, got %v", i, event.Old)
+		} else if e.oldInt != 0 && (event.Old == nil || event.Old.(*testtypes.FullStruct).IntField != e.oldInt) {
+			t.Errorf("Testing subscribe event %v. Expected old record with IntField %v, got %v", i, e.oldInt, event.Old)
+		}
+
+		if e.eventType == tormenta.EventDeleted && event.New != nil {
+			t.Errorf("Testing subscribe event %v. Expected no new record, got %v", i, event.New)
+		} else if e.newInt != 0 && (event.New == nil || event.New.(*testtypes.FullStruct).IntField != e.newInt) {
+			t.Errorf("Testing subscribe event %v. Expected new record with IntField %v, got %v", i, e.newInt, event.New)
+		}
+	}
+
+	// Both records of the multiple save
+	saved := map[interface{}]bool{another.ID: true, yetAnother.ID: true}
+	if !saved[received[2].ID] || !saved[received[3].ID] || received[2].ID == received[3].ID {
+		t.Errorf("Testing subscribe. Expected events for both records of a multiple save")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Testing subscribe. Expected no error on cancel, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Testing subscribe. Subscribe didn't return on cancel")
+	}
+
+	select {
+	case e := <-events:
+		t.Errorf("Testing subscribe. Didn't expect any more events, got %v", e)
+	default:
+	}
+}
+
+func Test_Subscribe_NoEntities(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	if err := db.Subscribe(context.Background(), func(tormenta.Event) {}); err == nil {
+		t.Error("Testing subscribe with no entity types. Expected an error")
+	}
+}
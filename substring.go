@@ -0,0 +1,202 @@
+package tormenta
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Suffix and substring searching.
+// Both need an extra index, so they have to be enabled on a field by field basis:
+// - `tormenta:"reversed"` indexes the value back to front,
+// i:root:indexName#reversed:eulav:entityID
+// so that 'ends with' becomes a regular 'starts with' on the reversed index.
+// - `tormenta:"trigram"` (see fuzzy.go) lets us find values containing every trigram
+// of the search string, which are then checked for the substring itself.
+
+const (
+	reversedIndex = "reversed"
+
+	ErrBlankInputEndsWithQuery = "Blank string is not valid input for 'ends with' query"
+	ErrBlankInputContainsQuery = "Blank string is not valid input for 'contains' query"
+	ErrFieldNotReversed        = "Field %s needs to be tagged 'reversed' for an 'ends with' query"
+	ErrFieldNotTrigram         = "Field %s needs to be tagged 'trigram' for a 'contains' query"
+)
+
+// Indexing
+
+func getReversedIndex(v reflect.Value, root []byte, id gouuidv6.UUID, indexName []byte, encoding stringEncoding) indexEntry {
+	return makeIndexEntry(root, id, derivedIndexName(indexName, reversedIndex), []byte(reverseString(encoding.normalise(v.String()))))
+}
+
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
+
+// Querying
+
+// EndsWith allows for string suffix filtering on fields tagged `tormenta:"reversed"`
+func (q *Query) EndsWith(indexName string, s string) *Query {
+	if s == "" {
+		q.err = errors.New(ErrBlankInputEndsWithQuery)
+		return q
+	}
+
+	if err := requireTag(q.target, indexName, tormentaTagReversed, ErrFieldNotReversed); err != nil {
+		q.err = err
+		return q
+	}
+
+	// Create the filter and add it on
+	encoding := targetStringEncoding(q.target, indexName)
+	reversed := reverseString(encoding.normalise(s))
+	q.addFilter(filter{
+		start:             reversed,
+		end:               reversed,
+		isStartsWithQuery: true,
+		indexName:         derivedIndexName(toIndexName(indexName), reversedIndex),
+		indexKind:         reflect.String,
+		stringEncoding:    encoding,
+	})
+
+	return q
+}
+
+// Contains allows for substring filtering on fields tagged `tormenta:"trigram"`
+func (q *Query) Contains(indexName string, s string) *Query {
+	if s == "" {
+		q.err = errors.New(ErrBlankInputContainsQuery)
+		return q
+	}
+
+	if err := requireTag(q.target, indexName, tormentaTagTrigram, ErrFieldNotTrigram); err != nil {
+		q.err = err
+		return q
+	}
+
+	// Create the filter and add it on
+	q.addFilter(filter{
+		substring: targetStringEncoding(q.target, indexName).normalise(s),
+		indexName: toIndexName(indexName),
+		indexKind: reflect.String,
+	})
+
+	return q
+}
+
+func requireTag(target interface{}, indexName, tag, errorMessage string) error {
+	field, err := structField(target, indexName)
+	if err != nil {
+		return err
+	}
+
+	if !isTaggedWith(field, tag) {
+		return fmt.Errorf(errorMessage, indexName)
+	}
+
+	return nil
+}
+
+// Filter mechanics for 'contains' queries
+
+func (f filter) isContainsQuery() bool {
+	return f.substring != ""
+}
+
+func (f *filter) queryContainsIDs(txn *badger.Txn) (idList, error) {
+	indexName := derivedIndexName(f.indexName, fuzzyTrigramIndex)
+	substring := []byte(f.substring)
+	matches := map[gouuidv6.UUID]bool{}
+
+	check := func(id gouuidv6.UUID, value []byte) {
+		if !keyIsOutsideDateRange(id, f.from, f.to) && bytes.Contains(value, substring) {
+			matches[id] = true
+		}
+	}
+
+	searchTrigrams := splitTrigrams(f.substring)
+
+	// Substrings shorter than a trigram could be part of any trigram,
+	// so we have to go through the whole trigram index
+	if len(searchTrigrams) == 0 {
+		prefix := newIndexKey(f.keyRoot, indexName, nil).bytes()
+		err := iteratePrefix(txn, prefix, true, func(item *badger.Item) error {
+			f.step.scanned()
+
+			id := extractID(item.Key())
+			if matches[id] || !bytes.Contains(indexContent(item.Key(), prefix), substring) {
+				return nil
+			}
+
+			return item.Value(func(val []byte) error {
+				check(id, val)
+				return nil
+			})
+		})
+
+		if err != nil {
+			return idList{}, err
+		}
+	} else {
+		// Otherwise the candidates are the values containing all the trigrams
+		shared := map[gouuidv6.UUID]int{}
+		values := map[gouuidv6.UUID][]byte{}
+
+		for _, trigram := range searchTrigrams {
+			prefix := append(newIndexMatchKey(f.keyRoot, indexName, []byte(trigram)).bytes(), []byte(keySeparator)...)
+			err := iteratePrefix(txn, prefix, true, func(item *badger.Item) error {
+				f.step.scanned()
+
+				id := extractID(item.Key())
+				shared[id]++
+
+				if _, found := values[id]; found {
+					return nil
+				}
+
+				return item.Value(func(val []byte) error {
+					values[id] = append([]byte{}, val...)
+					return nil
+				})
+			})
+
+			if err != nil {
+				return idList{}, err
+			}
+		}
+
+		for id, n := range shared {
+			if n == len(searchTrigrams) {
+				check(id, values[id])
+			}
+		}
+	}
+
+	var ids idList
+	for id := range matches {
+		ids = append(ids, id)
+	}
+
+	ids.sort(f.reverse)
+	return ids.limitOffset(f.limit, f.offset), nil
+}
+
+// validateContains checks the candidates against a 'contains' filter,
+// preserving the order of the candidates
+func (f *filter) validateContains(txn *badger.Txn, candidates idList) (idList, error) {
+	matches, err := f.queryContainsIDs(txn)
+	if err != nil {
+		return idList{}, err
+	}
+
+	return candidates.keep(matches), nil
+}
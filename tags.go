@@ -6,12 +6,22 @@ import (
 )
 
 const (
-	tormentaTag            = "tormenta"
-	tormentaTagNoIndex     = "noindex"
-	tormentaTagNestedIndex = "nested"
-	tormentaTagNoSave      = "-"
-	tormentaTagSplit       = "split"
-	tagSeparator           = ";"
+	tormentaTag              = "tormenta"
+	tormentaTagNoIndex       = "noindex"
+	tormentaTagNestedIndex   = "nested"
+	tormentaTagNoSave        = "-"
+	tormentaTagSplit         = "split"
+	tormentaTagFullText      = "fulltext"
+	tormentaTagTrigram       = "trigram"
+	tormentaTagReversed      = "reversed"
+	tormentaTagCaseSensitive = "casesensitive"
+	tormentaTagFold          = "fold"
+	tormentaTagCollate       = "collate"
+	tormentaTagOmitEmpty     = "omitempty"
+	tormentaTagSparse        = "sparse"
+	tormentaTagHistory       = "history"
+	tagSeparator             = ";"
+	tagValueSeparator        = "="
 )
 
 // Tormenta-specific tags
@@ -21,11 +31,23 @@ func getTormentaTags(field reflect.StructField) []string {
 	return strings.Split(compositeTag, tagSeparator)
 }
 
+// splitTag separates a tag of the form `name=value` into its name and value.
+// Tags without a value just return the name.
+func splitTag(tag string) (name, value string) {
+	components := strings.SplitN(tag, tagValueSeparator, 2)
+	if len(components) == 2 {
+		return components[0], components[1]
+	}
+
+	return components[0], ""
+}
+
 func isTaggedWith(field reflect.StructField, targetTags ...string) bool {
 	tags := getTormentaTags(field)
 	for _, tag := range tags {
+		tagName, _ := splitTag(tag)
 		for _, targetTag := range targetTags {
-			if tag == targetTag {
+			if tagName == targetTag {
 				return true
 			}
 		}
@@ -34,6 +56,18 @@ func isTaggedWith(field reflect.StructField, targetTags ...string) bool {
 	return false
 }
 
+// tagValue returns the value of a tag specified as `name=value`.
+// If the tag is present without a value, a blank string is returned.
+func tagValue(field reflect.StructField, targetTag string) string {
+	for _, tag := range getTormentaTags(field) {
+		if tagName, value := splitTag(tag); tagName == targetTag {
+			return value
+		}
+	}
+
+	return ""
+}
+
 // shouldIndex specifies whether a field should be indexed or not
 // according to the optional `tormenta:"noindex"` tag
 func shouldIndex(field reflect.StructField) bool {
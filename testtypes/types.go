@@ -1,9 +1,15 @@
 package testtypes
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"errors"
+	"math/big"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/buger/jsonparser"
 	"github.com/jpincas/gouuidv6"
 	"github.com/jpincas/tormenta"
 )
@@ -57,20 +63,45 @@ type DoubleNestedRelatedStruct struct {
 	tormenta.Model
 }
 
+// Decimal is a fixed point amount in hundredths,
+// indexed with its own encoding
+type Decimal int64
+
+func (d Decimal) IndexValue() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(d)^(1<<63))
+	return b, nil
+}
+
+func (d *Decimal) DecodeIndexValue(b []byte) error {
+	if len(b) != 8 {
+		return errors.New("invalid decimal index value")
+	}
+
+	*d = Decimal(binary.BigEndian.Uint64(b) ^ (1 << 63))
+	return nil
+}
+
 type FullStruct struct {
 	tormenta.Model
 
 	// Basic types
-	IntField          int
-	IDField           gouuidv6.UUID
-	AnotherIntField   int
-	StringField       string
-	MultipleWordField string `tormenta:"split"`
-	FloatField        float64
-	Float32Field      float32
-	AnotherFloatField float64
-	BoolField         bool
-	DateField         time.Time
+	IntField           int
+	IDField            gouuidv6.UUID
+	AnotherIntField    int
+	StringField        string
+	MultipleWordField  string `tormenta:"split"`
+	FullTextField      string `tormenta:"fulltext"`
+	TrigramField       string `tormenta:"trigram"`
+	ReversedField      string `tormenta:"reversed"`
+	CaseSensitiveField string `tormenta:"casesensitive"`
+	FoldField          string `tormenta:"fold"`
+	CollatedField      string `tormenta:"collate=de"`
+	FloatField         float64
+	Float32Field       float32
+	AnotherFloatField  float64
+	BoolField          bool
+	DateField          time.Time
 
 	// Fixed-length types
 	Int8Field  int8
@@ -84,6 +115,12 @@ type FullStruct struct {
 	Uint32Field uint32
 	Uint64Field uint64
 
+	// Big number types
+	BigIntField   *big.Int
+	BigFloatField *big.Float
+	BigRatField   *big.Rat
+	DecimalField  Decimal
+
 	// Slice types
 	IDSliceField     []gouuidv6.UUID
 	IntSliceField    []int
@@ -99,6 +136,7 @@ type FullStruct struct {
 	FloatMapField  map[string]float64
 	BoolMapField   map[string]bool
 	DateMapField   map[string]time.Time
+	AnyMapField    map[string]interface{}
 
 	// Defined types
 	DefinedIDField     DefinedID
@@ -127,6 +165,15 @@ type FullStruct struct {
 	// Named Struct
 	StructField MyStruct `tormenta:"nested"`
 
+	// Pointer types
+	IntPtrField    *int
+	StringPtrField *string
+	StructPtrField *MyStruct `tormenta:"nested"`
+
+	// Slices of structs
+	StructSliceField    []MyStruct  `tormenta:"nested"`
+	StructPtrSliceField []*MyStruct `tormenta:"nested"`
+
 	// Fields for trigger testing
 	TriggerString   string
 	Retrieved       bool
@@ -138,6 +185,9 @@ type FullStruct struct {
 	NoIndexTwoTags               string `tormenta:"noindex; split"`
 	NoIndexTwoTagsDifferentOrder string `tormenta:"split;noindex"`
 
+	// Fields for zero value testing
+	OmitEmptyIntField int `tormenta:"omitempty"`
+
 	// Fields for 'no save' testing
 	NoSaveSimple                string `tormenta:"-"`
 	NoSaveTwoTags               string `tormenta:"split;-"`
@@ -185,6 +235,78 @@ func (t *FullStruct) PostGet(ctx map[string]interface{}) {
 	t.Retrieved = true
 }
 
+func (t *FullStruct) PostGetRaw(raw json.RawMessage, ctx map[string]interface{}) (json.RawMessage, error) {
+	if sessionId, ok := ctx["sessionid"].(string); ok {
+		var err error
+		if raw, err = jsonparser.Set(raw, []byte(strconv.Quote(sessionId)), "TriggerString"); err != nil {
+			return nil, err
+		}
+	}
+
+	return jsonparser.Set(raw, []byte("true"), "Retrieved")
+}
+
+// FullStructSummary is a read only view of FullStruct
+type FullStructSummary struct {
+	tormenta.Model
+
+	IntField    int
+	StringField string
+}
+
+func (FullStructSummary) TormentaEntity() string {
+	return "FullStruct"
+}
+
+// Invoice keeps its full history
+type Invoice struct {
+	tormenta.Model `tormenta:"history"`
+
+	Number int
+	Amount float64
+}
+
+// Quote keeps its last 2 previous versions
+type Quote struct {
+	tormenta.Model `tormenta:"history=2"`
+
+	Amount float64
+}
+
+// Order only indexes the customer of open orders,
+// and doesn't index a zero discount.
+// The total and the domain of the email address are computed indexes.
+type Order struct {
+	tormenta.Model
+
+	Status   string
+	Customer string
+	Discount int `tormenta:"sparse"`
+	Qty      int
+	Price    float64
+	Email    string
+}
+
+func (o Order) PartialIndexes() []string {
+	return []string{"Customer"}
+}
+
+func (o Order) IndexIf(indexName string) bool {
+	return o.Status != "completed"
+}
+
+func (o Order) ComputedIndexes() map[string]interface{} {
+	var domain string
+	if at := strings.LastIndex(o.Email, "@"); at >= 0 {
+		domain = o.Email[at+1:]
+	}
+
+	return map[string]interface{}{
+		"Total":       float64(o.Qty) * o.Price,
+		"EmailDomain": domain,
+	}
+}
+
 type MiniStruct struct {
 	tormenta.Model
 
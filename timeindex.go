@@ -0,0 +1,46 @@
+package tormenta
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// Nanosecond time index encoding.
+// By default, times are indexed as unix seconds, so times within the same second
+// are indistinguishable in ranges and ordering.
+// With Options.NanosecondTimeIndex, times are indexed as unix nanoseconds,
+// in an int64 with the sign bit flipped, as for other signed integers.
+// The nanosecond range only covers the years 1678 to 2262, so times outside it
+// (including the zero time) are clamped to the minimum or maximum value.
+
+var (
+	minNanosecondTime = time.Unix(0, math.MinInt64)
+	maxNanosecondTime = time.Unix(0, math.MaxInt64)
+)
+
+// timeContent returns the index content for a time value
+func (o indexOptions) timeContent(t time.Time) interface{} {
+	if o.nanosecondTime {
+		return nanosecondTimeBytes(t)
+	}
+
+	return t
+}
+
+func nanosecondTimeBytes(t time.Time) []byte {
+	var nanoseconds int64
+
+	switch {
+	case t.Before(minNanosecondTime):
+		nanoseconds = math.MinInt64
+	case t.After(maxNanosecondTime):
+		nanoseconds = math.MaxInt64
+	default:
+		nanoseconds = t.UnixNano()
+	}
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(nanoseconds))
+	return flipInt(b)
+}
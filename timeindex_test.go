@@ -0,0 +1,40 @@
+package tormenta
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func Test_NanosecondTimeBytes_Order(t *testing.T) {
+	base := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	// In ascending order
+	values := []time.Time{
+		{},
+		time.Date(1600, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Unix(0, 0),
+		base,
+		base.Add(time.Nanosecond),
+		base.Add(time.Millisecond),
+		base.Add(time.Second),
+		time.Date(2300, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for i := 1; i < len(values); i++ {
+		a, b := nanosecondTimeBytes(values[i-1]), nanosecondTimeBytes(values[i])
+		if i == 1 {
+			// The zero time and 1600 are both clamped to the minimum
+			if !bytes.Equal(a, b) {
+				t.Errorf("Testing nanosecond time encoding. Expected %v and %v to be clamped to the same value", values[i-1], values[i])
+			}
+
+			continue
+		}
+
+		if bytes.Compare(a, b) >= 0 {
+			t.Errorf("Testing nanosecond time encoding. Expected %v to sort before %v", values[i-1], values[i])
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package tormenta
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Views
+// A view is a lighter struct that reads the records of another entity,
+// e.g. just the ID, Name and Total of a 40 field Order, for a list view or an alternative API representation.
+// Views implement EntityView to name the entity they read, and embed tormenta.Model like any other record.
+// Find, First, Get and the raw functions all accept views, and records are unmarshalled
+// straight into the view type, so fields which aren't in the view are never allocated.
+// Filters and ordering are worked out from the fields of the view, so any fields used in a query
+// need to be included in the view, with the same types and tags as on the entity.
+// Saving a view would lose the fields it leaves out, and deleting one would leave
+// their indexes behind, so views are read only.
+
+const (
+	ErrReadOnlyView = "%s is a read only view of %s, so can't be saved, deleted or reindexed"
+)
+
+// EntityView is implemented by view structs, returning the name of the entity
+// they are a view of, e.g. "Order" (which is case insensitive)
+type EntityView interface {
+	TormentaEntity() string
+}
+
+var typeEntityView = reflect.TypeOf((*EntityView)(nil)).Elem()
+
+// viewEntity returns the name of the entity which records (or slices of records)
+// of this type are a view of, if any
+func viewEntity(t reflect.Type) (string, bool) {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	t = derefType(t)
+	if !reflect.PtrTo(t).Implements(typeEntityView) {
+		return "", false
+	}
+
+	return reflect.New(t).Interface().(EntityView).TormentaEntity(), true
+}
+
+// checkNotView returns an error for records which are views
+func checkNotView(entity Record) error {
+	t := reflect.Indirect(reflect.ValueOf(entity)).Type()
+	if name, ok := viewEntity(t); ok {
+		return fmt.Errorf(ErrReadOnlyView, t.Name(), name)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package tormenta_test
+
+import (
+	"testing"
+
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+func Test_View(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	var fullStructs []tormenta.Record
+	for i := 1; i <= 5; i++ {
+		fullStructs = append(fullStructs, &testtypes.FullStruct{
+			IntField:    i,
+			StringField: "Test",
+			FloatField:  float64(i),
+		})
+	}
+
+	db.Save(fullStructs...)
+
+	// Find
+	var summaries []testtypes.FullStructSummary
+	n, err := db.Find(&summaries).Range("IntField", 2, 4).OrderBy("IntField").Reverse().Run()
+	if err != nil {
+		t.Fatalf("Testing find with view. Didn't expect error [%v]", err)
+	}
+
+	if n != 3 || len(summaries) != 3 {
+		t.Fatalf("Testing find with view. Expected 3 results, got %v", n)
+	}
+
+	for i, summary := range summaries {
+		expected := fullStructs[3-i].(*testtypes.FullStruct)
+		if summary.ID != expected.ID || summary.IntField != expected.IntField || summary.StringField != "Test" {
+			t.Errorf("Testing find with view. Result %v doesn't match the record: %v", i, summary)
+		}
+	}
+
+	// Count
+	if n, _ := db.Find(&summaries).Count(); n != 5 {
+		t.Errorf("Testing count with view. Expected 5, got %v", n)
+	}
+
+	// First
+	var summary testtypes.FullStructSummary
+	if n, _ := db.First(&summary).Match("IntField", 5).Run(); n != 1 || summary.IntField != 5 {
+		t.Errorf("Testing first with view. Expected IntField 5, got %v", summary.IntField)
+	}
+
+	// Get
+	summary = testtypes.FullStructSummary{}
+	if found, err := db.Get(&summary, fullStructs[0].GetID()); !found || err != nil || summary.IntField != 1 {
+		t.Errorf("Testing get with view. Expected to get record, got found %v, error %v, record %v", found, err, summary)
+	}
+
+	// Fields that aren't in the view can't be queried
+	if _, err := db.Find(&summaries).Match("FloatField", 1.0).Run(); err == nil {
+		t.Error("Testing find with view on a field not in the view. Expected an error")
+	}
+
+	// Pluck works as for the entity
+	plucked, err := db.Find(&summaries).Match("IntField", 2).Pluck("StringField")
+	if err != nil || len(plucked) != 1 || plucked[0]["StringField"] != "test" {
+		t.Errorf("Testing pluck with view. Expected 1 result, got %v (error %v)", plucked, err)
+	}
+}
+
+func Test_View_ReadOnly(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	fullStruct := testtypes.FullStruct{IntField: 1}
+	db.Save(&fullStruct)
+
+	summary := testtypes.FullStructSummary{}
+	db.Get(&summary, fullStruct.ID)
+
+	if _, err := db.Save(&summary); err == nil {
+		t.Error("Testing save of view. Expected an error")
+	}
+
+	if err := db.Delete(&summary); err == nil {
+		t.Error("Testing delete of view. Expected an error")
+	}
+
+	if err := db.RebuildIndexes(&summary); err == nil {
+		t.Error("Testing reindex of view. Expected an error")
+	}
+
+	// The record is untouched
+	var results []testtypes.FullStruct
+	if n, _ := db.Find(&results).Match("IntField", 1).Run(); n != 1 {
+		t.Errorf("Testing read only view. Expected the record to still be indexed, got %v results", n)
+	}
+}
@@ -0,0 +1,243 @@
+package tormenta
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Live queries
+// Watch subscribes to the index keys that a query depends on - those of its filters
+// and its order index - so that it is only re-evaluated when a write could change its results.
+// Queries that can't be narrowed down to particular indexes (no filters, full text and fuzzy searches,
+// IsZero/NotZero) are re-evaluated on any write to their entity type.
+// Bursts of writes are debounced, and the results are only reported when they have changed.
+// Badger subscriptions start asynchronously, so before the initial evaluation,
+// Watch writes a short lived marker key until the subscription sees it:
+// w:markerid
+// which makes sure that no writes are missed in between.
+
+const (
+	watchKeyPrefix = "w"
+
+	// Default time to wait after a write before re-evaluating a watched query
+	defaultWatchDebounce = 100 * time.Millisecond
+
+	// How often the marker key is written while waiting for the subscription to start,
+	// and how long it is kept for
+	watchMarkerInterval = 10 * time.Millisecond
+	watchMarkerTTL      = time.Minute
+)
+
+// Watch runs the query, calls fn with the results and then calls it again whenever they change,
+// with the IDs of the current results, and those added and removed since the last call.
+// For queries with an OrderBy, a change in the order of the results counts as a change.
+// It blocks until the context is cancelled (when it returns nil) or an error occurs,
+// so should normally be run in a goroutine.
+func (q *Query) Watch(ctx context.Context, fn func(ids, added, removed []gouuidv6.UUID)) error {
+	if q.err != nil {
+		return q.err
+	}
+
+	// The subscription is stopped whenever we return
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Writes are signalled on a buffered channel,
+	// so that any number of writes while waiting result in a single re-evaluation
+	changes := make(chan struct{}, 1)
+	started := make(chan struct{}, 1)
+	subscriptionErr := make(chan error, 1)
+	marker := watchMarkerKey()
+	prefixes := append(q.watchPrefixes(), marker)
+	go func() {
+		subscriptionErr <- q.db.KV.Subscribe(ctx, func(kvs *badger.KVList) error {
+			for _, kv := range kvs.Kv {
+				signal := changes
+				if bytes.Equal(kv.Key, marker) {
+					signal = started
+				}
+
+				select {
+				case signal <- struct{}{}:
+				default:
+				}
+			}
+
+			return nil
+		}, prefixes...)
+	}()
+
+	if err := q.db.awaitSubscription(ctx, marker, started, subscriptionErr); err != nil {
+		if err == ctx.Err() {
+			return nil
+		}
+
+		return err
+	}
+
+	current, err := q.watchIDs()
+	if err != nil {
+		return err
+	}
+
+	fn(current, current, nil)
+
+	debounce := q.db.Options.WatchDebounce
+	if debounce == 0 {
+		debounce = defaultWatchDebounce
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err := <-subscriptionErr:
+			if err == ctx.Err() {
+				return nil
+			}
+
+			return err
+
+		case <-changes:
+			select {
+			case <-time.After(debounce):
+			case <-ctx.Done():
+				return nil
+			}
+
+			// Writes during the debounce period are covered by this evaluation
+			select {
+			case <-changes:
+			default:
+			}
+
+			ids, err := q.watchIDs()
+			if err != nil {
+				return err
+			}
+
+			// Changes in order only count for ordered queries - otherwise the order
+			// isn't meaningful, e.g. the results of Or queries come in any order
+			added, removed := diffIDs(current, ids)
+			changed := len(added) > 0 || len(removed) > 0
+			if len(q.orderByIndexName) > 0 && !sameIDs(current, ids) {
+				changed = true
+			}
+
+			current = ids
+			if changed {
+				fn(ids, added, removed)
+			}
+		}
+	}
+}
+
+func watchMarkerKey() []byte {
+	return bytes.Join([][]byte{[]byte(watchKeyPrefix), newID().Bytes()}, []byte(keySeparator))
+}
+
+// awaitSubscription writes the marker key until the subscription signals that it has seen it
+func (db DB) awaitSubscription(ctx context.Context, marker []byte, started chan struct{}, subscriptionErr chan error) error {
+	ticker := time.NewTicker(watchMarkerInterval)
+	defer ticker.Stop()
+
+	for {
+		err := db.KV.Update(func(txn *badger.Txn) error {
+			return txn.SetEntry(badger.NewEntry(marker, nil).WithTTL(watchMarkerTTL))
+		})
+
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-started:
+			return nil
+		case err := <-subscriptionErr:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *Query) watchIDs() ([]gouuidv6.UUID, error) {
+	txn := q.db.KV.NewTransaction(false)
+	defer txn.Discard()
+
+	ids, err := q.resultIDs(txn)
+	return []gouuidv6.UUID(ids), err
+}
+
+// watchPrefixes are the key prefixes that writes affecting the results of the query will be under
+func (q *Query) watchPrefixes() [][]byte {
+	if len(q.filters) == 0 || q.ranker != nil {
+		return [][]byte{contentPrefix(q.keyRoot)}
+	}
+
+	indexNames := [][]byte{}
+	for _, f := range q.filters {
+		if f.zero != nil {
+			return [][]byte{contentPrefix(q.keyRoot)}
+		}
+
+		indexNames = append(indexNames, f.indexName)
+	}
+
+	if len(q.orderByIndexName) > 0 {
+		indexNames = append(indexNames, q.orderByIndex())
+	}
+
+	// No separator after the index name, so that
+	// derived indexes such as Field#collate are included
+	var prefixes [][]byte
+	for _, indexName := range indexNames {
+		prefixes = append(prefixes, bytes.Join([][]byte{[]byte(indexKeyPrefix), q.keyRoot, indexName}, []byte(keySeparator)))
+	}
+
+	return prefixes
+}
+
+// diffIDs returns the IDs that are in the new list but not the old, and vice versa
+func diffIDs(old, new []gouuidv6.UUID) (added, removed []gouuidv6.UUID) {
+	inOld := map[gouuidv6.UUID]bool{}
+	for _, id := range old {
+		inOld[id] = true
+	}
+
+	inNew := map[gouuidv6.UUID]bool{}
+	for _, id := range new {
+		inNew[id] = true
+		if !inOld[id] {
+			added = append(added, id)
+		}
+	}
+
+	for _, id := range old {
+		if !inNew[id] {
+			removed = append(removed, id)
+		}
+	}
+
+	return
+}
+
+func sameIDs(a, b []gouuidv6.UUID) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
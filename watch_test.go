@@ -0,0 +1,190 @@
+package tormenta_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jpincas/gouuidv6"
+	"github.com/jpincas/tormenta"
+	"github.com/jpincas/tormenta/testtypes"
+)
+
+type watchCall struct {
+	ids, added, removed []gouuidv6.UUID
+}
+
+func Test_Watch(t *testing.T) {
+	options := testDBOptions
+	options.WatchDebounce = 50 * time.Millisecond
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", options)
+	defer db.Close()
+
+	existing := testtypes.FullStruct{IntField: 3}
+	db.Save(&existing)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan watchCall, 100)
+	done := make(chan error)
+
+	var results []testtypes.FullStruct
+	go func() {
+		done <- db.Find(&results).Range("IntField", 2, 4).Watch(ctx, func(ids, added, removed []gouuidv6.UUID) {
+			calls <- watchCall{ids, added, removed}
+		})
+	}()
+
+	nextCall := func(testName string) watchCall {
+		select {
+		case call := <-calls:
+			return call
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Testing watch - %s. Timed out waiting for call", testName)
+		}
+
+		return watchCall{}
+	}
+
+	expectNoCall := func(testName string) {
+		select {
+		case call := <-calls:
+			t.Errorf("Testing watch - %s. Didn't expect a call, got %v", testName, call)
+		case <-time.After(300 * time.Millisecond):
+		}
+	}
+
+	// Initial results
+	call := nextCall("initial results")
+	if len(call.ids) != 1 || call.ids[0] != existing.ID || len(call.added) != 1 || len(call.removed) != 0 {
+		t.Errorf("Testing watch - initial results. Expected the existing record, got %v", call)
+	}
+
+	// The subscription is live before the initial results,
+	// so a write straight afterwards is seen - a new matching record
+	matching := testtypes.FullStruct{IntField: 2}
+	db.Save(&matching)
+
+	call = nextCall("new matching record")
+	if len(call.ids) != 2 || len(call.added) != 1 || call.added[0] != matching.ID || len(call.removed) != 0 {
+		t.Errorf("Testing watch - new matching record. Expected it to be added, got %v", call)
+	}
+
+	// Writes that don't change the results
+	db.Save(&testtypes.FullStruct{IntField: 10})
+	db.Save(&testtypes.RelatedStruct{StructIntField: 3})
+	matching.StringField = "changed"
+	db.Save(&matching)
+	expectNoCall("writes that don't change the results")
+
+	// A record moving out of the range
+	matching.IntField = 5
+	db.Save(&matching)
+
+	call = nextCall("record moving out of range")
+	if len(call.ids) != 1 || len(call.added) != 0 || len(call.removed) != 1 || call.removed[0] != matching.ID {
+		t.Errorf("Testing watch - record moving out of range. Expected it to be removed, got %v", call)
+	}
+
+	// A burst of writes is debounced
+	for i := 0; i < 10; i++ {
+		db.Save(&testtypes.FullStruct{IntField: 4})
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	noCalls, total := 0, 0
+	for len(calls) > 0 {
+		call := <-calls
+		noCalls++
+		total += len(call.added)
+	}
+
+	if total != 10 || noCalls >= 10 {
+		t.Errorf("Testing watch - burst of writes. Expected 10 records to be added in fewer than 10 calls, got %v in %v", total, noCalls)
+	}
+
+	// Deletion
+	db.Delete(&testtypes.FullStruct{}, existing.ID)
+	call = nextCall("deletion")
+	if len(call.removed) != 1 || call.removed[0] != existing.ID {
+		t.Errorf("Testing watch - deletion. Expected the record to be removed, got %v", call)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Testing watch. Expected no error on cancel, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Testing watch. Watch didn't return on cancel")
+	}
+}
+
+func Test_Watch_Or(t *testing.T) {
+	options := testDBOptions
+	options.WatchDebounce = 20 * time.Millisecond
+
+	db, _ := tormenta.OpenTestWithOptions("data/tests", options)
+	defer db.Close()
+
+	for i := 0; i < 20; i++ {
+		db.Save(&testtypes.FullStruct{IntField: i % 2})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := make(chan watchCall, 100)
+	var results []testtypes.FullStruct
+	go db.Find(&results).Or().Match("IntField", 0).Match("IntField", 1).Watch(ctx, func(ids, added, removed []gouuidv6.UUID) {
+		calls <- watchCall{ids, added, removed}
+	})
+
+	select {
+	case call := <-calls:
+		if len(call.ids) != 20 {
+			t.Errorf("Testing watch of or query - initial results. Expected 20 results, got %v", len(call.ids))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Testing watch of or query. Timed out waiting for initial results")
+	}
+
+	// Writes to the watched index which don't change the results,
+	// even though the results come back in a different order each time
+	for i := 0; i < 5; i++ {
+		db.Save(&testtypes.FullStruct{IntField: 99})
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	select {
+	case call := <-calls:
+		t.Errorf("Testing watch of or query. Didn't expect a call, got %v ids, %v added, %v removed", len(call.ids), len(call.added), len(call.removed))
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// A new matching record
+	added := testtypes.FullStruct{IntField: 1}
+	db.Save(&added)
+
+	select {
+	case call := <-calls:
+		if len(call.ids) != 21 || len(call.added) != 1 || call.added[0] != added.ID {
+			t.Errorf("Testing watch of or query - new record. Expected it to be added, got %v", call)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Testing watch of or query. Timed out waiting for new record")
+	}
+}
+
+func Test_Watch_QueryError(t *testing.T) {
+	db, _ := tormenta.OpenTestWithOptions("data/tests", testDBOptions)
+	defer db.Close()
+
+	var results []testtypes.FullStruct
+	err := db.Find(&results).Match("NoSuchField", 1).Watch(context.Background(), func(ids, added, removed []gouuidv6.UUID) {})
+	if err == nil {
+		t.Error("Testing watch of a query with an error. Expected an error")
+	}
+}
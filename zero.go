@@ -0,0 +1,188 @@
+package tormenta
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dgraph-io/badger"
+	"github.com/jpincas/gouuidv6"
+)
+
+// Zero value filters.
+// A record is 'zero' for a field if the field holds its zero value,
+// or if there is nothing in the index for it at all - which is the case for
+// nil pointers, empty slices and maps, sparse fields
+// and records saved before the field existed.
+// So IsZero = (all records - records in the index) + records indexed with the zero value,
+// and NotZero = records in the index - records indexed with the zero value.
+// IsZero has to go through the keys of every record of the entity type, so is relatively expensive.
+
+type zeroCheck struct {
+	// NotZero rather than IsZero
+	not bool
+
+	// The zero value of the field, if zero values are indexed as such
+	value interface{}
+
+	// The index which has an entry for every record in which the field is set.
+	// For maps, this is the index of keys.
+	presenceIndexName []byte
+}
+
+// IsZero filters for records where the field is empty, i.e. has its zero value or is not set at all
+func (q *Query) IsZero(indexName string) *Query {
+	return q.addZeroFilter(indexName, false)
+}
+
+// NotZero filters for records where the field is set to something other than its zero value
+func (q *Query) NotZero(indexName string) *Query {
+	return q.addZeroFilter(indexName, true)
+}
+
+func (q *Query) addZeroFilter(indexName string, not bool) *Query {
+	field, err := structField(q.target, indexName)
+	if err != nil {
+		q.err = err
+		return q
+	}
+
+	// Records left out of a conditional index can't be told apart from empty ones
+	if isConditionalIndex(q.target, indexName) {
+		q.err = fmt.Errorf(ErrPartialIndexZero, indexName)
+		return q
+	}
+
+	presenceIndexName := toIndexName(indexName)
+	if field.Type.Kind() == reflect.Map {
+		presenceIndexName = derivedIndexName(presenceIndexName, mapKeysIndex)
+	}
+
+	q.addFilter(filter{
+		indexName:      toIndexName(indexName),
+		indexKind:      field.Type.Kind(),
+		indexType:      field.Type,
+		stringEncoding: fieldStringEncoding(field),
+		zero: &zeroCheck{
+			not:               not,
+			value:             indexedZeroValue(field),
+			presenceIndexName: presenceIndexName,
+		},
+	})
+
+	return q
+}
+
+// indexedZeroValue returns the zero value of the field,
+// or nil if zero values of the field are never indexed as a single value
+func indexedZeroValue(field reflect.StructField) interface{} {
+	if isSparse(field) {
+		return nil
+	}
+
+	zero := reflect.Zero(field.Type).Interface()
+
+	if isCustomIndexType(field.Type) {
+		return zero
+	}
+
+	switch field.Type.Kind() {
+	case reflect.Slice, reflect.Map:
+		return nil
+
+	case reflect.Array:
+		if field.Type != reflect.TypeOf(gouuidv6.UUID{}) {
+			return nil
+		}
+
+	case reflect.Struct:
+		if _, ok := asTime(zero); !ok {
+			return nil
+		}
+
+	case reflect.String:
+		// Empty strings have no full text terms
+		if isTaggedWith(field, tormentaTagFullText) {
+			return nil
+		}
+	}
+
+	return zero
+}
+
+// Filter mechanics for zero value queries
+
+func (f filter) isZeroQuery() bool {
+	return f.zero != nil
+}
+
+func (f *filter) queryZeroIDs(txn *badger.Txn) (idList, error) {
+	// Records indexed with the zero value
+	zeroIDs := map[gouuidv6.UUID]bool{}
+	if f.zero.value != nil {
+		zeroContent, err := f.encodeInput(f.zero.value)
+		if err != nil {
+			return idList{}, err
+		}
+
+		zeroPrefix := append(newIndexMatchKey(f.keyRoot, f.indexName, zeroContent).bytes(), []byte(keySeparator)...)
+		if err := f.collectIDs(txn, zeroPrefix, zeroIDs); err != nil {
+			return idList{}, err
+		}
+	}
+
+	// Records in the index
+	indexedIDs := map[gouuidv6.UUID]bool{}
+	if err := f.collectIDs(txn, newIndexKey(f.keyRoot, f.zero.presenceIndexName, nil).bytes(), indexedIDs); err != nil {
+		return idList{}, err
+	}
+
+	var ids idList
+	if f.zero.not {
+		for id := range indexedIDs {
+			if !zeroIDs[id] {
+				ids = append(ids, id)
+			}
+		}
+	} else {
+		// All records
+		allIDs := map[gouuidv6.UUID]bool{}
+		contentPrefix := append(newContentKey(f.keyRoot).bytes(), []byte(keySeparator)...)
+		if err := f.collectIDs(txn, contentPrefix, allIDs); err != nil {
+			return idList{}, err
+		}
+
+		for id := range allIDs {
+			if !indexedIDs[id] || zeroIDs[id] {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	ids.sort(f.reverse)
+	return ids.limitOffset(f.limit, f.offset), nil
+}
+
+// collectIDs adds the IDs of all the keys with the prefix, within the date range
+func (f *filter) collectIDs(txn *badger.Txn, prefix []byte, ids map[gouuidv6.UUID]bool) error {
+	return iteratePrefix(txn, prefix, false, func(item *badger.Item) error {
+		f.step.scanned()
+
+		id := extractID(item.Key())
+		if !keyIsOutsideDateRange(id, f.from, f.to) {
+			ids[id] = true
+		}
+
+		return nil
+	})
+}
+
+// validateZero checks the candidates against a zero value filter,
+// preserving the order of the candidates
+func (f *filter) validateZero(txn *badger.Txn, candidates idList) (idList, error) {
+	matches, err := f.queryZeroIDs(txn)
+	if err != nil {
+		return idList{}, err
+	}
+
+	return candidates.keep(matches), nil
+}